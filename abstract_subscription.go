@@ -0,0 +1,51 @@
+package eventbus
+
+import (
+	"context"
+)
+
+// AbstractSubscription is the type-agnostic part of Subscription.
+type AbstractSubscription interface {
+	ID() uint64
+	CreatorStack() []byte
+	Finish(ctx context.Context) bool
+	Done() <-chan struct{}
+	Backlog() uint
+	QueueSize() uint
+	DeliveredCount() uint64
+	ReceivedCount() uint64
+	OnOverflow() OnOverflow
+}
+
+var _ AbstractSubscription = (*Subscription[struct{}, struct{}])(nil)
+
+// OnOverflow returns the overflow policy of the subscription.
+func (sub *Subscription[T, E]) OnOverflow() OnOverflow {
+	return sub.onOverflow
+}
+
+// AbstractSubscriptions returns all current subscriptions of the given topic.
+func (bus *EventBus) AbstractSubscriptions(topic any) []AbstractSubscription {
+	state := bus.getTopicState(topic)
+	if state == nil {
+		return nil
+	}
+	subs := state.loadSubscriptions()
+	result := make([]AbstractSubscription, 0, len(subs))
+	for _, sub := range subs {
+		result = append(result, sub.(AbstractSubscription))
+	}
+	return result
+}
+
+// Topics returns all topics having at least one subscription.
+func (bus *EventBus) Topics() []any {
+	var result []any
+	bus.topics.Range(func(topic, state any) bool {
+		if len(state.(*topicState).loadSubscriptions()) > 0 {
+			result = append(result, topic)
+		}
+		return true
+	})
+	return result
+}
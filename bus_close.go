@@ -0,0 +1,36 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+)
+
+// Close finishes all the subscriptions of the EventBus; after that
+// subscribing and publishing fail with ErrBusClosed.
+func (bus *EventBus) Close(ctx context.Context) error {
+	if !bus.closed.CompareAndSwap(false, true) {
+		return ErrBusClosed
+	}
+	var err error
+	bus.topics.Range(func(_, _state any) bool {
+		state := _state.(*topicState)
+		// locking to wait for the subscribing which started before the closing
+		if !state.Lock(ctx) {
+			err = fmt.Errorf("%w: %w", ErrContextDone, ctx.Err())
+			return false
+		}
+		subs := state.loadSubscriptions()
+		state.Unlock()
+		for _, sub := range subs {
+			sub.(interface{ setCloseReason(error) }).setCloseReason(ErrBusClosed)
+			sub.(AbstractSubscription).Finish(ctx)
+		}
+		return true
+	})
+	return err
+}
+
+// IsClosed returns true if the EventBus is closed (see Close).
+func (bus *EventBus) IsClosed() bool {
+	return bus.closed.Load()
+}
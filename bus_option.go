@@ -0,0 +1,67 @@
+package eventbus
+
+import (
+	"context"
+)
+
+// BusOption is an option of an EventBus (see New).
+type BusOption interface {
+	applyToBus(*busConfig)
+}
+
+type busConfig struct {
+	sendHooks           []SendHook
+	contextPropagators  []ContextPropagator
+	diagnosticsEnabled  bool
+	retainLastEvents    bool
+	strictTypes         bool
+	panicOnTypeMismatch bool
+}
+
+type BusOptions []BusOption
+
+func (s BusOptions) Config() busConfig {
+	cfg := busConfig{}
+	for _, opt := range s {
+		opt.applyToBus(&cfg)
+	}
+	return cfg
+}
+
+// SendHook is called on every SendEventWithCustomTopic (and SendEvent).
+type SendHook interface {
+	// BeforeSend is called before the event is dispatched to the subscribers.
+	// The returned context is used for the dispatching and is passed
+	// to the respective AfterSend.
+	BeforeSend(ctx context.Context, topic, event any) context.Context
+
+	// AfterSend is called after the event is dispatched to all the subscribers
+	// (including the deferred ones).
+	AfterSend(ctx context.Context, topic, event any, result SendEventResult)
+}
+
+// BusOptionSendHook adds a SendHook to the EventBus.
+type BusOptionSendHook struct {
+	SendHook
+}
+
+func (opt BusOptionSendHook) applyToBus(cfg *busConfig) {
+	cfg.sendHooks = append(cfg.sendHooks, opt.SendHook)
+}
+
+// ContextPropagator defines which parts of the publisher's context are
+// carried to the subscribers receiving EventWithContext.
+type ContextPropagator interface {
+	// PropagateContext returns deliveryCtx enriched with whatever
+	// is needed from publisherCtx.
+	PropagateContext(publisherCtx, deliveryCtx context.Context) context.Context
+}
+
+// BusOptionContextPropagator adds a ContextPropagator to the EventBus.
+type BusOptionContextPropagator struct {
+	ContextPropagator
+}
+
+func (opt BusOptionContextPropagator) applyToBus(cfg *busConfig) {
+	cfg.contextPropagators = append(cfg.contextPropagators, opt.ContextPropagator)
+}
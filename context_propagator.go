@@ -0,0 +1,153 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/facebookincubator/go-belt"
+)
+
+// ContextPropagatorFunc is a ContextPropagator defined by a function.
+type ContextPropagatorFunc func(publisherCtx, deliveryCtx context.Context) context.Context
+
+var _ ContextPropagator = ContextPropagatorFunc(nil)
+
+func (fn ContextPropagatorFunc) PropagateContext(
+	publisherCtx, deliveryCtx context.Context,
+) context.Context {
+	return fn(publisherCtx, deliveryCtx)
+}
+
+// ContextPropagators is a ContextPropagator that applies all of its items.
+type ContextPropagators []ContextPropagator
+
+var _ ContextPropagator = ContextPropagators(nil)
+
+func (s ContextPropagators) PropagateContext(
+	publisherCtx, deliveryCtx context.Context,
+) context.Context {
+	for _, propagator := range s {
+		deliveryCtx = propagator.PropagateContext(publisherCtx, deliveryCtx)
+	}
+	return deliveryCtx
+}
+
+// PropagateValues is a ContextPropagator that copies the values
+// of the given keys from the publisher's context.
+type PropagateValues []any
+
+var _ ContextPropagator = PropagateValues(nil)
+
+func (keys PropagateValues) PropagateContext(
+	publisherCtx, deliveryCtx context.Context,
+) context.Context {
+	for _, key := range keys {
+		value := publisherCtx.Value(key)
+		if value == nil {
+			continue
+		}
+		deliveryCtx = context.WithValue(deliveryCtx, key, value)
+	}
+	return deliveryCtx
+}
+
+// PropagateDeadline is a ContextPropagator that copies the deadline
+// of the publisher's context (but not its cancellation).
+//
+// No timer is started unless Done of the resulting context is used.
+type PropagateDeadline struct{}
+
+var _ ContextPropagator = PropagateDeadline{}
+
+func (PropagateDeadline) PropagateContext(
+	publisherCtx, deliveryCtx context.Context,
+) context.Context {
+	deadline, ok := publisherCtx.Deadline()
+	if !ok {
+		return deliveryCtx
+	}
+	if cur, ok := deliveryCtx.Deadline(); ok && !cur.After(deadline) {
+		return deliveryCtx
+	}
+	return &deadlineContext{Context: deliveryCtx, deadline: deadline}
+}
+
+// deadlineContext is a context with a deadline, which (unlike
+// context.WithDeadline) does not need to be cancelled to release
+// its resources: the timer is started only by Done and is stopped
+// when the deadline is reached or the parent is done.
+type deadlineContext struct {
+	context.Context
+	deadline time.Time
+
+	doneOnce sync.Once
+	done     chan struct{}
+}
+
+func (ctx *deadlineContext) Deadline() (time.Time, bool) {
+	return ctx.deadline, true
+}
+
+func (ctx *deadlineContext) Done() <-chan struct{} {
+	ctx.doneOnce.Do(func() {
+		ctx.done = make(chan struct{})
+		var (
+			locker        sync.Mutex
+			timer         *time.Timer
+			stopAfterFunc func() bool
+		)
+		closeDone := func() {
+			locker.Lock()
+			defer locker.Unlock()
+			select {
+			case <-ctx.done:
+				return
+			default:
+			}
+			close(ctx.done)
+			timer.Stop()
+			stopAfterFunc()
+		}
+		locker.Lock()
+		defer locker.Unlock()
+		timer = time.AfterFunc(time.Until(ctx.deadline), closeDone)
+		stopAfterFunc = context.AfterFunc(ctx.Context, closeDone)
+	})
+	return ctx.done
+}
+
+func (ctx *deadlineContext) Err() error {
+	if err := ctx.Context.Err(); err != nil {
+		return err
+	}
+	if !time.Now().Before(ctx.deadline) {
+		return context.DeadlineExceeded
+	}
+	return nil
+}
+
+// PropagateBelt is a ContextPropagator that copies the go-belt Belt
+// (logger, tracer, fields, trace IDs, etc) of the publisher's context.
+type PropagateBelt struct{}
+
+var _ ContextPropagator = PropagateBelt{}
+
+func (PropagateBelt) PropagateContext(
+	publisherCtx, deliveryCtx context.Context,
+) context.Context {
+	return belt.CtxWithBelt(deliveryCtx, belt.CtxBelt(publisherCtx))
+}
+
+// OptionContextPropagator adds a ContextPropagator to the subscription
+// (in addition to ones of the EventBus, see BusOptionContextPropagator).
+//
+// It has effect only on subscriptions receiving EventWithContext
+// (see SubscribeWithContext).
+type OptionContextPropagator struct {
+	ContextPropagator
+}
+
+func (opt OptionContextPropagator) apply(cfg *config) {
+	cfg.contextPropagators = append(cfg.contextPropagators, opt.ContextPropagator)
+}
@@ -0,0 +1,239 @@
+package eventbus
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"reflect"
+	"runtime/debug"
+	"slices"
+	"time"
+
+	"github.com/xaionaro-go/xcontext"
+)
+
+// deferredSend is a pending delivery of an event to a subscription which
+// could not receive it immediately (see OnOverflowWait and OnOverflowWaitOrClose).
+type deferredSend struct {
+	subscription   AbstractSubscription
+	eventType      string
+	event          reflect.Value
+	subDone        reflect.Value
+	timeout        time.Duration // zero means no timeout
+	closeOnTimeout bool
+	deadline       time.Time
+
+	// turn is to be waited for before sending (see OptionOrderedDelivery),
+	// and turnDone is closed when the deferred send is finished.
+	turn        reflect.Value
+	turnDone    chan struct{}
+	waitingTurn bool
+	sendCase    reflect.SelectCase
+
+	// lockEventChan returns the event channel with the subscription's
+	// eventChanLocker read-locked (to prevent the channel from closing);
+	// the returned value is invalid if the subscription is already closed.
+	lockEventChan   func() reflect.Value
+	unlockEventChan func()
+	addDelivered    func(delta int64)
+	unsubscribe     func(reason error)
+	diagnosticsID   uint64
+}
+
+func newDeferredSend[T, E any](
+	ctx context.Context,
+	bus *EventBus,
+	topic T,
+	sub *Subscription[T, E],
+	event E,
+) *deferredSend {
+	d := &deferredSend{
+		subscription: sub,
+		eventType:    fmt.Sprintf("%T", event),
+		// not using reflect.ValueOf(event), since it is invalid for nil interfaces
+		event:   reflect.ValueOf(&event).Elem(),
+		subDone: reflect.ValueOf(sub.Done()),
+		lockEventChan: func() reflect.Value {
+			sub.eventChanLocker.RLock()
+			if sub.eventChan == nil {
+				return reflect.Value{}
+			}
+			return reflect.ValueOf(sub.eventChan)
+		},
+		unlockEventChan: sub.eventChanLocker.RUnlock,
+		addDelivered:    sub.addDeliveredCount,
+		unsubscribe: func(reason error) {
+			if reason != nil {
+				sub.setCloseReason(reason)
+			}
+			unsubscribeWithCustomTopic(xcontext.DetachDone(ctx), bus, topic, sub, true)
+		},
+	}
+	switch onOverflow := sub.onOverflow.(type) {
+	case OnOverflowWait:
+		d.timeout = time.Duration(onOverflow)
+	case OnOverflowWaitOrClose:
+		d.timeout = time.Duration(onOverflow)
+		d.closeOnTimeout = true
+	}
+	if sub.orderedDelivery {
+		var turn <-chan struct{}
+		turn, d.turnDone = sub.enqueueDeferredSend()
+		if turn != nil {
+			d.turn = reflect.ValueOf(turn)
+			d.waitingTurn = true
+		}
+	}
+	return d
+}
+
+// release is to be called when the deferred send is finished (either way).
+func (d *deferredSend) release() {
+	if d.turnDone != nil {
+		close(d.turnDone)
+	}
+}
+
+const (
+	selectCaseCtxDone = iota
+	selectCaseTimeout
+	selectCaseFirstSend
+)
+
+// sendEventsDeferred is the blocking part of sending an event to subscriptions.
+//
+// All the deferred sends are waited for by a single reflect.Select in
+// the publisher's goroutine (instead of a goroutine per subscription).
+func sendEventsDeferred(
+	ctx context.Context,
+	bus *EventBus,
+	topic any,
+	sends []*deferredSend,
+) (sentCount, dropCount uint) {
+	// locking in a consistent order to avoid deadlocks between
+	// concurrent publishers while an unsubscriber is waiting for a lock
+	slices.SortFunc(sends, func(a, b *deferredSend) int {
+		return cmp.Compare(a.subscription.ID(), b.subscription.ID())
+	})
+
+	var publisherStack []byte
+	if bus.diagnostics != nil {
+		publisherStack = debug.Stack()
+	}
+	now := time.Now()
+
+	cases := make([]reflect.SelectCase, selectCaseFirstSend, selectCaseFirstSend+2*len(sends))
+	cases[selectCaseCtxDone] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())}
+	pending := make([]*deferredSend, 0, len(sends))
+	for _, d := range sends {
+		eventChan := d.lockEventChan()
+		if !eventChan.IsValid() {
+			d.unlockEventChan()
+			d.release()
+			dropCount++
+			continue
+		}
+		if d.timeout > 0 {
+			d.deadline = now.Add(d.timeout)
+		}
+		if bus.diagnostics != nil {
+			d.diagnosticsID = bus.diagnostics.addBlockedPublisher(&blockedPublisher{
+				topic:          topic,
+				eventType:      d.eventType,
+				since:          now,
+				publisherStack: publisherStack,
+				subscription:   d.subscription,
+			})
+		}
+		d.addDelivered(1)
+		pending = append(pending, d)
+		sendCase := reflect.SelectCase{Dir: reflect.SelectSend, Chan: eventChan, Send: d.event}
+		if d.waitingTurn {
+			d.sendCase = sendCase
+			sendCase = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: d.turn}
+		}
+		cases = append(cases,
+			sendCase,
+			reflect.SelectCase{Dir: reflect.SelectRecv, Chan: d.subDone},
+		)
+	}
+
+	finish := func(idx int) *deferredSend {
+		d := pending[idx]
+		d.unlockEventChan()
+		d.release()
+		if bus.diagnostics != nil {
+			bus.diagnostics.removeBlockedPublisher(d.diagnosticsID)
+		}
+		lastIdx := len(pending) - 1
+		pending[idx] = pending[lastIdx]
+		pending = pending[:lastIdx]
+		copy(cases[selectCaseFirstSend+2*idx:], cases[selectCaseFirstSend+2*lastIdx:])
+		cases = cases[:selectCaseFirstSend+2*lastIdx]
+		return d
+	}
+
+	var timer *time.Timer
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	for len(pending) > 0 {
+		var nearestDeadline time.Time
+		for _, d := range pending {
+			if !d.deadline.IsZero() && (nearestDeadline.IsZero() || d.deadline.Before(nearestDeadline)) {
+				nearestDeadline = d.deadline
+			}
+		}
+		// a zero Chan makes reflect.Select to ignore the case
+		cases[selectCaseTimeout] = reflect.SelectCase{Dir: reflect.SelectRecv}
+		if !nearestDeadline.IsZero() {
+			if timer == nil {
+				timer = time.NewTimer(time.Until(nearestDeadline))
+			} else {
+				timer.Reset(time.Until(nearestDeadline))
+			}
+			cases[selectCaseTimeout] = reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timer.C)}
+		}
+
+		chosen, _, _ := reflect.Select(cases)
+		switch chosen {
+		case selectCaseCtxDone:
+			for len(pending) > 0 {
+				finish(len(pending) - 1).addDelivered(-1)
+				dropCount++
+			}
+		case selectCaseTimeout:
+			now := time.Now()
+			for idx := len(pending) - 1; idx >= 0; idx-- {
+				if d := pending[idx]; d.deadline.IsZero() || d.deadline.After(now) {
+					continue
+				}
+				d := finish(idx)
+				d.addDelivered(-1)
+				dropCount++
+				if d.closeOnTimeout {
+					d.unsubscribe(ErrSubscriptionOverflow)
+				}
+			}
+		default:
+			idx := (chosen - selectCaseFirstSend) / 2
+			isSent := (chosen-selectCaseFirstSend)%2 == 0
+			if d := pending[idx]; isSent && d.waitingTurn {
+				// the previous deferred send is finished, now it is our turn
+				d.waitingTurn = false
+				cases[chosen] = d.sendCase
+				continue
+			}
+			d := finish(idx)
+			if isSent {
+				sentCount++
+			} else {
+				d.addDelivered(-1)
+				d.unsubscribe(nil)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,150 @@
+package eventbus
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BusOptionDiagnostics enables recording of which publishers are
+// blocked on which subscriptions, and of the stack traces of the
+// subscriptions' creators (see EventBus.Diagnostics).
+//
+// It makes subscribing and deferred sending noticeably more expensive.
+type BusOptionDiagnostics bool
+
+func (opt BusOptionDiagnostics) applyToBus(cfg *busConfig) {
+	cfg.diagnosticsEnabled = bool(opt)
+}
+
+type diagnostics struct {
+	locker            sync.Mutex
+	nextID            uint64
+	blockedPublishers map[uint64]*blockedPublisher
+}
+
+type blockedPublisher struct {
+	topic          any
+	eventType      string
+	since          time.Time
+	publisherStack []byte
+	subscription   AbstractSubscription
+}
+
+func newDiagnostics() *diagnostics {
+	return &diagnostics{
+		blockedPublishers: map[uint64]*blockedPublisher{},
+	}
+}
+
+func (d *diagnostics) addBlockedPublisher(item *blockedPublisher) uint64 {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	d.nextID++
+	d.blockedPublishers[d.nextID] = item
+	return d.nextID
+}
+
+func (d *diagnostics) removeBlockedPublisher(id uint64) {
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	delete(d.blockedPublishers, id)
+}
+
+var lastSubscriptionID atomic.Uint64
+
+// SubscriptionDiagnostics describes a subscription.
+type SubscriptionDiagnostics struct {
+	ID           uint64 `json:"id"`
+	Topic        string `json:"topic"`
+	Backlog      uint   `json:"backlog"`
+	QueueSize    uint   `json:"queue_size"`
+	CreatorStack string `json:"creator_stack,omitempty"`
+}
+
+// BlockedPublisherDiagnostics describes a publisher waiting
+// in the deferred phase of SendEventWithCustomTopic for a subscription.
+type BlockedPublisherDiagnostics struct {
+	Topic          string                  `json:"topic"`
+	EventType      string                  `json:"event_type"`
+	Since          time.Time               `json:"since"`
+	PublisherStack string                  `json:"publisher_stack"`
+	Subscription   SubscriptionDiagnostics `json:"subscription"`
+}
+
+// DiagnosticsReport is the graph of publishers blocked on subscriptions.
+type DiagnosticsReport struct {
+	BlockedPublishers []BlockedPublisherDiagnostics `json:"blocked_publishers"`
+}
+
+// Diagnostics returns the current DiagnosticsReport.
+//
+// Returns false if the diagnostics are not enabled (see BusOptionDiagnostics).
+func (bus *EventBus) Diagnostics() (DiagnosticsReport, bool) {
+	d := bus.diagnostics
+	if d == nil {
+		return DiagnosticsReport{}, false
+	}
+	d.locker.Lock()
+	defer d.locker.Unlock()
+	report := DiagnosticsReport{
+		BlockedPublishers: make([]BlockedPublisherDiagnostics, 0, len(d.blockedPublishers)),
+	}
+	for _, item := range d.blockedPublishers {
+		report.BlockedPublishers = append(report.BlockedPublishers, BlockedPublisherDiagnostics{
+			Topic:          fmt.Sprintf("%#+v", item.topic),
+			EventType:      item.eventType,
+			Since:          item.since,
+			PublisherStack: string(item.publisherStack),
+			Subscription:   subscriptionDiagnostics(item.topic, item.subscription),
+		})
+	}
+	sort.Slice(report.BlockedPublishers, func(i, j int) bool {
+		return report.BlockedPublishers[i].Since.Before(report.BlockedPublishers[j].Since)
+	})
+	return report, true
+}
+
+func subscriptionDiagnostics(
+	topic any,
+	sub AbstractSubscription,
+) SubscriptionDiagnostics {
+	return SubscriptionDiagnostics{
+		ID:           sub.ID(),
+		Topic:        fmt.Sprintf("%#+v", topic),
+		Backlog:      sub.Backlog(),
+		QueueSize:    sub.QueueSize(),
+		CreatorStack: string(sub.CreatorStack()),
+	}
+}
+
+// WriteTo writes a human-readable representation of the report.
+func (report DiagnosticsReport) WriteTo(w io.Writer) (int64, error) {
+	var buf strings.Builder
+	if len(report.BlockedPublishers) == 0 {
+		buf.WriteString("no blocked publishers\n")
+	}
+	now := time.Now()
+	for _, item := range report.BlockedPublishers {
+		fmt.Fprintf(
+			&buf,
+			"publisher of %s on topic %s is blocked for %v on subscription #%d (backlog %d/%d)\n",
+			item.EventType, item.Topic, now.Sub(item.Since).Round(time.Millisecond),
+			item.Subscription.ID, item.Subscription.Backlog, item.Subscription.QueueSize,
+		)
+		fmt.Fprintf(&buf, "\tpublisher stack:\n%s\n", indent(item.PublisherStack))
+		if item.Subscription.CreatorStack != "" {
+			fmt.Fprintf(&buf, "\tsubscription creator stack:\n%s\n", indent(item.Subscription.CreatorStack))
+		}
+	}
+	n, err := io.WriteString(w, buf.String())
+	return int64(n), err
+}
+
+func indent(s string) string {
+	return "\t\t" + strings.ReplaceAll(strings.TrimRight(s, "\n"), "\n", "\n\t\t")
+}
@@ -0,0 +1,14 @@
+package eventbus
+
+import (
+	"errors"
+)
+
+var (
+	ErrContextDone          = errors.New("the context is done")
+	ErrBusClosed            = errors.New("the event bus is closed")
+	ErrCallbackTypeMismatch = errors.New("the callback has a type not matching the subscription")
+	ErrNotSubscribed        = errors.New("not subscribed")
+	ErrTopicTypeMismatch    = errors.New("the topic is used with a different event type")
+	ErrSubscriptionOverflow = errors.New("the subscription is closed due to an overflow")
+)
@@ -2,8 +2,8 @@ package eventbus
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"math"
 	"sync"
 	"sync/atomic"
 
@@ -13,15 +13,47 @@ import (
 )
 
 type EventBus struct {
-	chanLocker
-	subscriptions map[any]map[any]struct{}
+	// legacyLocker backs the deprecated Lock, TryLock and Unlock.
+	legacyLocker chanLocker
+	topics       sync.Map // topic -> *topicState
+	topicTypes   sync.Map // topic -> reflect.Type (see BusOptionStrictTypes)
+	diagnostics  *diagnostics
+	closed       atomic.Bool
+	busConfig
 }
 
-func New() *EventBus {
-	return &EventBus{
-		chanLocker:    make(chanLocker, 1),
-		subscriptions: map[any]map[any]struct{}{},
+func New(opts ...BusOption) *EventBus {
+	bus := &EventBus{
+		legacyLocker: make(chanLocker, 1),
+		busConfig:    BusOptions(opts).Config(),
 	}
+	if bus.diagnosticsEnabled {
+		bus.diagnostics = newDiagnostics()
+	}
+	return bus
+}
+
+// Lock locks a mutex of the EventBus.
+//
+// Deprecated: the EventBus does not use this mutex anymore (subscribing locks
+// only the topic, and publishing does not lock at all), thus locking it
+// does not prevent subscribing, unsubscribing or publishing.
+func (bus *EventBus) Lock(ctx context.Context) bool {
+	return bus.legacyLocker.Lock(ctx)
+}
+
+// TryLock is the same as Lock, but does not wait.
+//
+// Deprecated: see Lock.
+func (bus *EventBus) TryLock(ctx context.Context) bool {
+	return bus.legacyLocker.TryLock(ctx)
+}
+
+// Unlock unlocks the mutex locked by Lock or TryLock.
+//
+// Deprecated: see Lock.
+func (bus *EventBus) Unlock() {
+	bus.legacyLocker.Unlock()
 }
 
 type SendEventResult struct {
@@ -46,91 +78,264 @@ func SendEventWithCustomTopic[T, E any](
 	bus *EventBus,
 	topic T,
 	event E,
-) (result SendEventResult) {
-	if isTraceEnabled(ctx) {
-		ctx = belt.WithField(ctx, "topic", fmt.Sprintf("%#+v", topic))
-		logger.Tracef(ctx, "SendEventWithCustomTopic[%T, %T]", topic, event)
-		defer func() {
-			logger.Tracef(ctx, "/SendEventWithCustomTopic[%T, %T]: %v", topic, event, result)
-		}()
+) SendEventResult {
+	s := pendingSend[T, E]{bus: bus, topic: topic, event: event}
+	s.begin(ctx)
+	s.finish()
+	return s.result
+}
+
+// SendEventWithError is the same as SendEvent, but also returns
+// the reason the event was not sent (if it was not): ErrContextDone,
+// ErrBusClosed or ErrTopicTypeMismatch.
+func SendEventWithError[E any](
+	ctx context.Context,
+	bus *EventBus,
+	event E,
+) (SendEventResult, error) {
+	var zeroValue E
+	return SendEventWithCustomTopicWithError(ctx, bus, zeroValue, event)
+}
+
+// SendEventWithCustomTopicWithError is the same as SendEventWithCustomTopic,
+// but also returns the reason the event was not sent (if it was not):
+// ErrContextDone, ErrBusClosed or ErrTopicTypeMismatch.
+func SendEventWithCustomTopicWithError[T, E any](
+	ctx context.Context,
+	bus *EventBus,
+	topic T,
+	event E,
+) (SendEventResult, error) {
+	s := pendingSend[T, E]{bus: bus, topic: topic, event: event}
+	s.begin(ctx)
+	s.finish()
+	return s.result, s.err
+}
+
+// pendingSend is an event being sent to subscriptions: begin performs
+// the immediate (non-blocking) phase of sending, and finish performs
+// the deferred (blocking) phase.
+type pendingSend[T, E any] struct {
+	ctx           context.Context
+	bus           *EventBus
+	topic         T
+	event         E
+	hookCtxs      []context.Context
+	deferredSends []*deferredSend
+	traceEnabled  bool
+	traceFinish   bool
+
+	// handle is set if the event is sent via a Topic handle.
+	handle *Topic[T, E]
+
+	// result is the result of the immediate phase until finish is called,
+	// and the final result after that.
+	result SendEventResult
+
+	// err is the reason the event was not sent to the subscriptions.
+	err error
+}
+
+// begin is the non-blocking part of sending an event.
+func (s *pendingSend[T, E]) begin(ctx context.Context) {
+	s.traceEnabled = isTraceEnabled(ctx)
+	if s.traceEnabled {
+		ctx = belt.WithField(ctx, "topic", fmt.Sprintf("%#+v", s.topic))
+		logger.Tracef(ctx, "SendEventWithCustomTopic[%T, %T]", s.topic, s.event)
+		s.traceFinish = true
 	}
-	var deferredSending []*Subscription[T, E]
+	if s.err = checkSend[T, E](ctx, s.bus, s.topic); s.err != nil {
+		s.ctx = ctx
+		return
+	}
+	s.beginChecked(ctx)
+}
 
-	// bus locking zone (here we cannot wait, and should act swiftly)
-	if !bus.Lock(ctx) {
-		result.DropCountImmediate = math.MaxUint
+// beginChecked is the same as begin, but without checking
+// if the event could be sent (see checkSend).
+func (s *pendingSend[T, E]) beginChecked(ctx context.Context) {
+	// the hooks are to be called before reading the subscriptions: e.g. a WAL
+	// subscription relies on that the event is either appended to the log
+	// before it subscribes, or is sent to it.
+	s.runHooks(ctx)
+	if s.handle != nil {
+		s.dispatchTyped(s.handle.loadTypedSubscriptions(s.ctx, s.event))
 		return
 	}
-	func() {
-		defer bus.Unlock()
-		if bus.subscriptions[topic] == nil {
-			if isTraceEnabled(ctx) {
-				logger.Tracef(ctx, "no subscriptions")
-			}
-			return
+	s.dispatch(loadSubscriptionsForSend(s.bus, s.topic, s.event))
+}
+
+// checkSend returns the reason an event of type E cannot be sent to the topic.
+func checkSend[T, E any](
+	ctx context.Context,
+	bus *EventBus,
+	topic T,
+) error {
+	if bus.closed.Load() {
+		return ErrBusClosed
+	}
+	if err := checkEventType[T, E](bus, topic); err != nil {
+		bus.reportTypeMismatch(ctx, err)
+		return err
+	}
+	return nil
+}
+
+// loadSubscriptionsForSend returns the subscriptions to send an event to
+// (and retains the event if BusOptionRetainLastEvents is set).
+func loadSubscriptionsForSend[T, E any](
+	bus *EventBus,
+	topic T,
+	event E,
+) []any {
+	if bus.retainLastEvents {
+		state := bus.lockTopicStateRetained(topic)
+		defer state.retainedLocker.Unlock()
+		state.retained, state.hasRetained = event, true
+		return state.loadSubscriptions()
+	}
+	if state := bus.getTopicState(topic); state != nil {
+		return state.loadSubscriptions()
+	}
+	return nil
+}
+
+// runHooks runs the BeforeSend hooks.
+func (s *pendingSend[T, E]) runHooks(ctx context.Context) {
+	if len(s.bus.sendHooks) > 0 {
+		s.hookCtxs = make([]context.Context, len(s.bus.sendHooks))
+		for idx, hook := range s.bus.sendHooks {
+			ctx = hook.BeforeSend(ctx, s.topic, s.event)
+			s.hookCtxs[idx] = ctx
 		}
-		select {
-		case <-ctx.Done():
-			result.DropCountImmediate = uint(len(bus.subscriptions[topic]))
-			return
+	}
+	s.ctx = ctx
+}
+
+// dispatch sends the event to the given subscriptions.
+func (s *pendingSend[T, E]) dispatch(subs []any) {
+	if !s.checkDispatch(len(subs)) {
+		return
+	}
+	var busDeliveryCtx context.Context
+	for _, _sub := range subs {
+		switch sub := _sub.(type) {
+		case *Subscription[T, E]:
+			s.sendTo(sub)
+		case *Subscription[T, EventWithContext[E]]:
+			s.sendWithContextTo(sub, &busDeliveryCtx)
 		default:
+			logger.Errorf(s.ctx, "invalid type %T, expected %T", _sub, (*Subscription[T, E])(nil))
 		}
-		for _sub := range bus.subscriptions[topic] {
-			sub, ok := _sub.(*Subscription[T, E])
-			if !ok {
-				logger.Errorf(ctx, "invalid type %T, expected %T", _sub, (*Subscription[E, E])(nil))
-				continue
-			}
-			switch r := sub.sendEvent(ctx, event, true); r {
-			case sendEventToSubResultSent:
-				result.SentCountImmediate++
-			case sendEventToSubResultPiled:
-				result.PiledCount++
-			case sendEventToSubResultDropped:
-				result.DropCountImmediate++
-			case sendEventToSubResultDroppedUnsubscribe:
-				result.DropCountImmediate++
-				unsubscribeWithCustomTopic(xcontext.DetachDone(ctx), bus, topic, sub, false)
-			case sendEventToSubResultUnsubscribe:
-				unsubscribeWithCustomTopic(xcontext.DetachDone(ctx), bus, topic, sub, false)
-			case sendEventToSubResultDeferred:
-				deferredSending = append(deferredSending, sub)
-			default:
-				panic(fmt.Errorf("unexpected value: %d", r))
-			}
-		}
-	}()
+	}
+}
 
-	// bus-lock-free zone (here we can wait)
-
-	if len(deferredSending) > 0 {
-		var successCount, dropCount atomic.Uint64
-		var wg sync.WaitGroup
-		for _, sub := range deferredSending {
-			wg.Add(1)
-			go func(sub *Subscription[T, E]) {
-				defer wg.Done()
-				switch r := sub.sendEvent(ctx, event, false); r {
-				case sendEventToSubResultSent:
-					successCount.Add(1)
-				case sendEventToSubResultDropped:
-					dropCount.Add(1)
-				case sendEventToSubResultDroppedUnsubscribe:
-					dropCount.Add(1)
-					unsubscribeWithCustomTopic(xcontext.DetachDone(ctx), bus, topic, sub, true)
-				case sendEventToSubResultUnsubscribe:
-					unsubscribeWithCustomTopic(xcontext.DetachDone(ctx), bus, topic, sub, true)
-				default:
-					panic(fmt.Errorf("unexpected value: %d", r))
-				}
-			}(sub)
+// dispatchTyped is the same as dispatch, but for the subscriptions
+// already sorted by type (see Topic).
+func (s *pendingSend[T, E]) dispatchTyped(typedSubs *typedSubscriptions[T, E]) {
+	if !s.checkDispatch(len(typedSubs.subs) + len(typedSubs.subsWithCtx)) {
+		return
+	}
+	for _, sub := range typedSubs.subs {
+		s.sendTo(sub)
+	}
+	var busDeliveryCtx context.Context
+	for _, sub := range typedSubs.subsWithCtx {
+		s.sendWithContextTo(sub, &busDeliveryCtx)
+	}
+}
+
+// checkDispatch returns true if the event should be sent to the subscriptions.
+func (s *pendingSend[T, E]) checkDispatch(subCount int) bool {
+	// non-blocking zone (here we cannot wait, and should act swiftly)
+	if subCount == 0 {
+		if s.traceEnabled {
+			logger.Tracef(s.ctx, "no subscriptions")
 		}
-		wg.Wait()
-		result.SentCountDeferred = uint(successCount.Load())
-		result.DropCountDeferred = uint(dropCount.Load())
+		return false
+	}
+	select {
+	case <-s.ctx.Done():
+		s.result.DropCountImmediate = uint(subCount)
+		s.err = fmt.Errorf("%w: %w", ErrContextDone, s.ctx.Err())
+		return false
+	default:
 	}
+	return true
+}
+
+func (s *pendingSend[T, E]) sendTo(sub *Subscription[T, E]) {
+	if sendEventImmediate(s.ctx, s.bus, s.topic, sub, s.event, s.traceEnabled, &s.result) {
+		s.deferredSends = append(s.deferredSends, newDeferredSend(s.ctx, s.bus, s.topic, sub, s.event))
+	}
+}
+
+func (s *pendingSend[T, E]) sendWithContextTo(
+	sub *Subscription[T, EventWithContext[E]],
+	busDeliveryCtx *context.Context,
+) {
+	if *busDeliveryCtx == nil {
+		*busDeliveryCtx = ContextPropagators(s.bus.contextPropagators).PropagateContext(s.ctx, context.Background())
+	}
+	eventWithContext := EventWithContext[E]{
+		Context: ContextPropagators(sub.contextPropagators).PropagateContext(s.ctx, *busDeliveryCtx),
+		Event:   s.event,
+	}
+	if sendEventImmediate(s.ctx, s.bus, s.topic, sub, eventWithContext, s.traceEnabled, &s.result) {
+		s.deferredSends = append(s.deferredSends, newDeferredSend(s.ctx, s.bus, s.topic, sub, eventWithContext))
+	}
+}
 
-	return
+// finish is the blocking part of sending an event (here we can wait);
+// it sets the final result.
+func (s *pendingSend[T, E]) finish() {
+	if len(s.deferredSends) > 0 {
+		s.result.SentCountDeferred, s.result.DropCountDeferred = sendEventsDeferred(s.ctx, s.bus, s.topic, s.deferredSends)
+	}
+	for idx := len(s.hookCtxs) - 1; idx >= 0; idx-- {
+		s.bus.sendHooks[idx].AfterSend(s.hookCtxs[idx], s.topic, s.event, s.result)
+	}
+	if s.traceFinish {
+		logger.Tracef(s.ctx, "/SendEventWithCustomTopic[%T, %T]: %v", s.topic, s.event, s.result)
+	}
+}
+
+// sendEventImmediate is the non-blocking part of sending an event to a subscription.
+// It returns true if the sending should be continued by sendEventDeferred.
+func sendEventImmediate[T, E any](
+	ctx context.Context,
+	bus *EventBus,
+	topic T,
+	sub *Subscription[T, E],
+	event E,
+	traceEnabled bool,
+	result *SendEventResult,
+) bool {
+	var r sendEventToSubResult
+	if traceEnabled {
+		r = sub.sendEvent(ctx, event, true)
+	} else {
+		r = sub.doSendEvent(ctx, event, true)
+	}
+	switch r {
+	case sendEventToSubResultSent:
+		result.SentCountImmediate++
+	case sendEventToSubResultPiled:
+		result.PiledCount++
+	case sendEventToSubResultDropped:
+		result.DropCountImmediate++
+	case sendEventToSubResultDroppedUnsubscribe:
+		result.DropCountImmediate++
+		sub.setCloseReason(ErrSubscriptionOverflow)
+		unsubscribeWithCustomTopic(xcontext.DetachDone(ctx), bus, topic, sub, true)
+	case sendEventToSubResultUnsubscribe:
+		unsubscribeWithCustomTopic(xcontext.DetachDone(ctx), bus, topic, sub, true)
+	case sendEventToSubResultDeferred:
+		return true
+	default:
+		panic(fmt.Errorf("unexpected value: %d", r))
+	}
+	return false
 }
 
 func Subscribe[E any](
@@ -147,35 +352,73 @@ func SubscribeWithCustomTopic[T, E any](
 	bus *EventBus,
 	topic T,
 	opts ...Option,
-) (_ret *Subscription[T, E]) {
+) *Subscription[T, E] {
+	sub, err := subscribeWithCustomTopic[T, E](ctx, bus, topic, opts...)
+	if errors.Is(err, ErrCallbackTypeMismatch) {
+		logger.Error(ctx, err)
+	}
+	return sub
+}
+
+// SubscribeWithError is the same as Subscribe, but returns the reason
+// of a failure: ErrContextDone, ErrBusClosed, ErrCallbackTypeMismatch
+// or ErrTopicTypeMismatch.
+func SubscribeWithError[E any](
+	ctx context.Context,
+	bus *EventBus,
+	opts ...Option,
+) (*Subscription[E, E], error) {
+	var zeroValue E
+	return SubscribeWithCustomTopicWithError[E, E](ctx, bus, zeroValue, opts...)
+}
+
+// SubscribeWithCustomTopicWithError is the same as SubscribeWithCustomTopic,
+// but returns the reason of a failure: ErrContextDone, ErrBusClosed,
+// ErrCallbackTypeMismatch or ErrTopicTypeMismatch.
+func SubscribeWithCustomTopicWithError[T, E any](
+	ctx context.Context,
+	bus *EventBus,
+	topic T,
+	opts ...Option,
+) (*Subscription[T, E], error) {
+	return subscribeWithCustomTopic[T, E](ctx, bus, topic, opts...)
+}
+
+func subscribeWithCustomTopic[T, E any](
+	ctx context.Context,
+	bus *EventBus,
+	topic T,
+	opts ...Option,
+) (_ret *Subscription[T, E], _err error) {
 	if isTraceEnabled(ctx) {
 		var sample E
 		ctx = belt.WithField(ctx, "topic", fmt.Sprintf("%#+v", topic))
 		logger.Tracef(ctx, "SubscribeWithCustomTopic[%T]", sample)
 		defer func() {
-			logger.Tracef(ctx, "/SubscribeWithCustomTopic[%T]: %p", sample, _ret)
+			logger.Tracef(ctx, "/SubscribeWithCustomTopic[%T]: %p %v", sample, _ret, _err)
 		}()
 	}
+	if bus.closed.Load() {
+		return nil, ErrBusClosed
+	}
+	if err := checkEventType[T, E](bus, topic); err != nil {
+		bus.reportTypeMismatch(ctx, err)
+		return nil, err
+	}
 	sub := newSubscription[T, E](ctx, bus, topic, opts...)
 	defer sub.readier.Trigger()
+	if err := sub.checkCallbackTypes(); err != nil {
+		sub.Cancel()
+		return nil, err
+	}
 
-	if _beforeSubscribed := sub.beforeSubscribed; _beforeSubscribed != nil {
-		beforeSubscribed, ok := _beforeSubscribed.(SubscriptionCallback[T, E])
-		if !ok {
-			logger.Errorf(ctx, "invalid type %T, expected %T", _beforeSubscribed, (SubscriptionCallback[T, E])(nil))
-			return nil
-		}
-		beforeSubscribed(ctx, sub)
+	if beforeSubscribed := sub.beforeSubscribed; beforeSubscribed != nil {
+		beforeSubscribed.(SubscriptionCallback[T, E])(ctx, sub)
 		if isTraceEnabled(ctx) {
 			logger.Tracef(ctx, "finished beforeSubscribed")
 		}
 	}
-	if _onSubscribed := sub.onSubscribed; _onSubscribed != nil {
-		onSubscribed, ok := _onSubscribed.(SubscriptionCallback[T, E])
-		if !ok {
-			logger.Errorf(ctx, "invalid type %T, expected %T", _onSubscribed, (SubscriptionCallback[T, E])(nil))
-			return nil
-		}
+	if onSubscribed := sub.onSubscribed; onSubscribed != nil {
 		sub.eventChanLocker.Lock()
 		defer func() {
 			go func() {
@@ -185,20 +428,48 @@ func SubscribeWithCustomTopic[T, E any](
 					}
 					sub.eventChanLocker.Unlock()
 				}()
-				onSubscribed(ctx, sub)
+				onSubscribed.(SubscriptionCallback[T, E])(ctx, sub)
 			}()
 		}()
 	}
 
-	if !bus.Lock(ctx) {
-		return nil
+	state := bus.lockTopicState(ctx, topic)
+	if state == nil {
+		sub.Cancel()
+		return nil, fmt.Errorf("%w: %w", ErrContextDone, ctx.Err())
 	}
-	defer bus.Unlock()
-	if bus.subscriptions[topic] == nil {
-		bus.subscriptions[topic] = map[any]struct{}{}
+	defer state.Unlock()
+	if bus.closed.Load() {
+		sub.Cancel()
+		return nil, ErrBusClosed
 	}
-	bus.subscriptions[topic][sub] = struct{}{}
-	return sub
+	if !sub.receiveRetained {
+		state.addSubscription(sub)
+		return sub, nil
+	}
+	state.retainedLocker.Lock()
+	defer state.retainedLocker.Unlock()
+	state.addSubscription(sub)
+	sub.sendRetainedEvent(ctx, state)
+	return sub, nil
+}
+
+// checkCallbackTypes returns ErrCallbackTypeMismatch if a callback option
+// of the subscription is not a SubscriptionCallback[T, E].
+func (sub *Subscription[T, E]) checkCallbackTypes() error {
+	for _, callback := range []abstractSubscriptionCallback{
+		sub.beforeSubscribed,
+		sub.onSubscribed,
+		sub.onUnsubscribe,
+	} {
+		if callback == nil {
+			continue
+		}
+		if _, ok := callback.(SubscriptionCallback[T, E]); !ok {
+			return fmt.Errorf("%w: %T, expected %T", ErrCallbackTypeMismatch, callback, (SubscriptionCallback[T, E])(nil))
+		}
+	}
+	return nil
 }
 
 func Unsubscribe[E any](
@@ -206,17 +477,18 @@ func Unsubscribe[E any](
 	bus *EventBus,
 	sub *Subscription[E, E],
 ) bool {
-	return unsubscribe(ctx, bus, sub, true)
+	return UnsubscribeWithError(ctx, bus, sub) == nil
 }
 
-func unsubscribe[E any](
+// UnsubscribeWithError is the same as Unsubscribe, but returns the reason
+// of a failure: ErrNotSubscribed or ErrContextDone.
+func UnsubscribeWithError[E any](
 	ctx context.Context,
 	bus *EventBus,
 	sub *Subscription[E, E],
-	lockBus bool,
-) bool {
+) error {
 	var zeroValue E
-	return unsubscribeWithCustomTopic(ctx, bus, zeroValue, sub, lockBus)
+	return unsubscribeWithCustomTopic(ctx, bus, zeroValue, sub, true)
 }
 
 func UnsubscribeWithCustomTopic[T, E any](
@@ -225,6 +497,17 @@ func UnsubscribeWithCustomTopic[T, E any](
 	topic T,
 	sub *Subscription[T, E],
 ) bool {
+	return unsubscribeWithCustomTopic(ctx, bus, topic, sub, true) == nil
+}
+
+// UnsubscribeWithCustomTopicWithError is the same as UnsubscribeWithCustomTopic,
+// but returns the reason of a failure: ErrNotSubscribed or ErrContextDone.
+func UnsubscribeWithCustomTopicWithError[T, E any](
+	ctx context.Context,
+	bus *EventBus,
+	topic T,
+	sub *Subscription[T, E],
+) error {
 	return unsubscribeWithCustomTopic(ctx, bus, topic, sub, true)
 }
 
@@ -233,8 +516,8 @@ func unsubscribeWithCustomTopic[T, E any](
 	bus *EventBus,
 	topic T,
 	sub *Subscription[T, E],
-	lockBus bool,
-) bool {
+	lockTopic bool,
+) error {
 	sub.Cancel()
 	eventChan := func() chan E {
 		sub.eventChanLocker.RLock()
@@ -242,18 +525,13 @@ func unsubscribeWithCustomTopic[T, E any](
 		return sub.eventChan
 	}()
 	if eventChan == nil {
-		return false
+		return ErrNotSubscribed
 	}
 	go func() {
 		sub.eventChanLocker.Lock()
 		defer sub.eventChanLocker.Unlock()
-		if _onUnsubscribe := sub.onUnsubscribe; _onUnsubscribe != nil {
-			onUnsubscribe, ok := _onUnsubscribe.(SubscriptionCallback[T, E])
-			if !ok {
-				logger.Errorf(ctx, "invalid type %T, expected %T", _onUnsubscribe, (SubscriptionCallback[T, E])(nil))
-			} else {
-				onUnsubscribe(ctx, sub)
-			}
+		if onUnsubscribe := sub.onUnsubscribe; onUnsubscribe != nil {
+			onUnsubscribe.(SubscriptionCallback[T, E])(ctx, sub)
 		}
 		if sub.eventChan == nil {
 			return
@@ -263,18 +541,19 @@ func unsubscribeWithCustomTopic[T, E any](
 		sub.finished.Trigger()
 	}()
 
-	if lockBus {
-		if !bus.Lock(ctx) {
-			return false
-		}
-		defer bus.Unlock()
+	state := bus.getTopicState(topic)
+	if state == nil {
+		return ErrNotSubscribed
 	}
-	if bus.subscriptions[topic] == nil {
-		return false
+	if lockTopic {
+		if !state.Lock(ctx) {
+			return fmt.Errorf("%w: %w", ErrContextDone, ctx.Err())
+		}
+		defer state.Unlock()
 	}
-	if _, ok := bus.subscriptions[topic][sub]; !ok {
-		return false
+	if !state.removeSubscription(sub) {
+		return ErrNotSubscribed
 	}
-	delete(bus.subscriptions[topic], sub)
-	return true
+	state.removeIfUnused(bus, topic)
+	return nil
 }
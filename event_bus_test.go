@@ -2,8 +2,13 @@ package eventbus
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
 	"runtime"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -128,6 +133,683 @@ func TestEventBus(t *testing.T) {
 	}
 }
 
+func TestSubscribeWithContext(t *testing.T) {
+	type ctxKey struct{}
+	type otherCtxKey struct{}
+
+	bus := New()
+	sub := SubscribeWithContext[int](
+		context.Background(), bus,
+		OptionContextPropagator{PropagateValues{ctxKey{}}},
+		OptionContextPropagator{PropagateDeadline{}},
+	)
+	defer sub.Finish(context.Background())
+
+	deadline := time.Now().Add(time.Hour)
+	ctx, cancelFn := context.WithDeadline(context.Background(), deadline)
+	ctx = context.WithValue(ctx, ctxKey{}, "value")
+	ctx = context.WithValue(ctx, otherCtxKey{}, "other value")
+	r := SendEvent(ctx, bus, 1)
+	require.Equal(t, SendEventResult{
+		SentCountImmediate: 1,
+	}, r)
+	cancelFn()
+
+	ev := <-sub.EventChan()
+	require.Equal(t, 1, ev.Event)
+	require.Equal(t, "value", ev.Context.Value(ctxKey{}))
+	require.Nil(t, ev.Context.Value(otherCtxKey{}))
+	evDeadline, ok := ev.Context.Deadline()
+	require.True(t, ok)
+	require.Equal(t, deadline, evDeadline)
+	require.NoError(t, ev.Context.Err(), "the publisher's cancellation is not supposed to be propagated")
+}
+
+func TestPropagateDeadline(t *testing.T) {
+	publisherCtx, cancelFn := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelFn()
+	ctx := PropagateDeadline{}.PropagateContext(publisherCtx, context.Background())
+	require.NoError(t, ctx.Err())
+	<-ctx.Done()
+	require.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+
+	publisherCtx, cancelFn = context.WithTimeout(context.Background(), time.Hour)
+	defer cancelFn()
+	deliveryCtx, deliveryCancelFn := context.WithCancel(context.Background())
+	ctx = PropagateDeadline{}.PropagateContext(publisherCtx, deliveryCtx)
+	deliveryCancelFn()
+	<-ctx.Done()
+	require.ErrorIs(t, ctx.Err(), context.Canceled)
+}
+
+func TestRetainedEvents(t *testing.T) {
+	ctx := context.Background()
+	bus := New(BusOptionRetainLastEvents(true))
+
+	SendEvent(ctx, bus, 1)
+	SendEvent(ctx, bus, 2)
+	retained, ok := bus.RetainedEvent(0)
+	require.True(t, ok)
+	require.Equal(t, 2, retained)
+
+	sub := Subscribe[int](ctx, bus, OptionReceiveRetained(true))
+	defer sub.Finish(ctx)
+	require.Equal(t, 2, <-sub.EventChan())
+
+	otherSub := Subscribe[int](ctx, bus)
+	defer otherSub.Finish(ctx)
+	select {
+	case ev := <-otherSub.EventChan():
+		t.Fatalf("unexpected event %v", ev)
+	default:
+	}
+
+	bus.SetRetainedEvent(0, 3)
+	require.Equal(t, map[any]any{0: 3}, bus.RetainedEvents())
+}
+
+func TestTopicLocking(t *testing.T) {
+	ctx := context.Background()
+	bus := New()
+	stateA := bus.getOrCreateTopicState("A")
+	require.True(t, stateA.Lock(ctx))
+
+	timeoutCtx, cancelFn := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancelFn()
+	require.Nil(t, SubscribeWithCustomTopic[string, int](timeoutCtx, bus, "A"), "the topic is locked")
+
+	subB := SubscribeWithCustomTopic[string, int](ctx, bus, "B")
+	require.NotNil(t, subB, "other topics are not supposed to be locked")
+	defer subB.Finish(ctx)
+	require.Equal(t, uint(1), SendEventWithCustomTopic(ctx, bus, "B", 1).SentCountImmediate)
+	require.Equal(t, SendEventResult{}, SendEventWithCustomTopic(ctx, bus, "A", 1), "publishing is not supposed to lock")
+
+	stateA.Unlock()
+	subA := SubscribeWithCustomTopic[string, int](ctx, bus, "A")
+	require.NotNil(t, subA)
+	subA.Finish(ctx)
+}
+
+func TestTopicStateRemoval(t *testing.T) {
+	ctx := context.Background()
+	countTopicStates := func(bus *EventBus) int {
+		count := 0
+		bus.topics.Range(func(_, _ any) bool {
+			count++
+			return true
+		})
+		return count
+	}
+
+	bus := New()
+	for idx := range 100 {
+		sub := SubscribeWithCustomTopic[int, int](ctx, bus, idx)
+		require.NotNil(t, sub)
+		require.True(t, UnsubscribeWithCustomTopic(ctx, bus, idx, sub))
+	}
+	require.Zero(t, countTopicStates(bus), "the states of dynamic topics are supposed to be removed")
+
+	_, err := GetTopicWithCustomTopic[string, int](ctx, bus, "handle")
+	require.NoError(t, err)
+	sub := SubscribeWithCustomTopic[string, int](ctx, bus, "handle")
+	require.True(t, UnsubscribeWithCustomTopic(ctx, bus, "handle", sub))
+	require.Equal(t, 1, countTopicStates(bus), "the states referenced by Topic handles are supposed to be kept")
+
+	bus = New(BusOptionRetainLastEvents(true))
+	sub = SubscribeWithCustomTopic[string, int](ctx, bus, "retained")
+	SendEventWithCustomTopic(ctx, bus, "retained", 1)
+	require.True(t, UnsubscribeWithCustomTopic(ctx, bus, "retained", sub))
+	retained, ok := bus.RetainedEvent("retained")
+	require.True(t, ok, "the states with a retained event are supposed to be kept")
+	require.Equal(t, 1, retained)
+
+	// a subscribing racing with the removal is not lost
+	bus = New()
+	sub = SubscribeWithCustomTopic[string, int](ctx, bus, "racing")
+	state := bus.getTopicState("racing")
+	require.True(t, state.Lock(ctx))
+	racingSub := make(chan *Subscription[string, int])
+	go func() {
+		racingSub <- SubscribeWithCustomTopic[string, int](ctx, bus, "racing", OptionQueueSize(1))
+	}()
+	time.Sleep(10 * time.Millisecond) // waiting for the subscribing to get the state
+	require.True(t, state.removeSubscription(sub))
+	state.removeIfUnused(bus, "racing")
+	state.Unlock()
+	sub = <-racingSub
+	require.NotNil(t, sub)
+	defer sub.Finish(ctx)
+	require.Equal(t, uint(1), SendEventWithCustomTopic(ctx, bus, "racing", 1).SentCountImmediate)
+}
+
+func TestSendEventAsync(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	bus := New()
+
+	fastSub := Subscribe[int](ctx, bus)
+	defer fastSub.Finish(ctx)
+	slowSub := Subscribe[int](ctx, bus, OptionQueueSize(0), OptionOnOverflow(OnOverflowWait(0)))
+	defer slowSub.Finish(ctx)
+
+	h := SendEventAsync(ctx, bus, 1)
+	require.Equal(t, SendEventResult{SentCountImmediate: 1}, h.ImmediateResult())
+	require.Equal(t, 1, <-fastSub.EventChan())
+	select {
+	case <-h.Done():
+		t.Fatal("the deferred send is complete before the event is received")
+	default:
+	}
+
+	waitCtx, waitCancelFn := context.WithCancel(ctx)
+	waitCancelFn()
+	_, err := h.Result(waitCtx)
+	require.ErrorIs(t, err, context.Canceled)
+
+	require.Equal(t, 1, <-slowSub.EventChan())
+	r, err := h.Result(ctx)
+	require.NoError(t, err)
+	require.Equal(t, SendEventResult{SentCountImmediate: 1, SentCountDeferred: 1}, r)
+
+	// no deferred sends
+	slowSub.Finish(ctx)
+	h = SendEventAsync(ctx, bus, 2)
+	<-h.Done()
+	r, err = h.Result(ctx)
+	require.NoError(t, err)
+	require.Equal(t, SendEventResult{SentCountImmediate: 1}, r)
+}
+
+func TestOrderedDelivery(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	bus := New()
+
+	const count = 100
+	sub := Subscribe[int](ctx, bus, OptionQueueSize(1), OptionOrderedDelivery(true))
+	defer sub.Finish(ctx)
+	pileSub := Subscribe[int](ctx, bus, OptionQueueSize(1), OptionOnOverflow(OnOverflowPileUpOrClose(count, 0)))
+	defer pileSub.Finish(ctx)
+
+	var handles []*SendEventHandle
+	for i := range count {
+		handles = append(handles, SendEventAsync(ctx, bus, i))
+	}
+	for i := range count {
+		require.Equal(t, i, <-sub.EventChan())
+		require.Equal(t, i, <-pileSub.EventChan())
+	}
+	for _, h := range handles {
+		r, err := h.Result(ctx)
+		require.NoError(t, err)
+		require.Equal(t, uint(0), r.DropCountImmediate+r.DropCountDeferred)
+	}
+}
+
+func TestSendEvents(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	bus := New(BusOptionRetainLastEvents(true))
+
+	sub := Subscribe[int](ctx, bus, OptionQueueSize(3))
+	defer sub.Finish(ctx)
+	dropSub := Subscribe[int](ctx, bus, OptionQueueSize(1), OptionOnOverflow(OnOverflowDrop{}))
+	defer dropSub.Finish(ctx)
+
+	r := SendEvents(ctx, bus, []int{1, 2, 3})
+	require.Equal(t, SendEventsResult{
+		Total: SendEventResult{SentCountImmediate: 4, DropCountImmediate: 2},
+		Events: []SendEventResult{
+			{SentCountImmediate: 2},
+			{SentCountImmediate: 1, DropCountImmediate: 1},
+			{SentCountImmediate: 1, DropCountImmediate: 1},
+		},
+	}, r)
+	for _, expected := range []int{1, 2, 3} {
+		require.Equal(t, expected, <-sub.EventChan())
+	}
+	require.Equal(t, 1, <-dropSub.EventChan())
+	retained, ok := bus.RetainedEvent(0)
+	require.True(t, ok)
+	require.Equal(t, 3, retained)
+
+	require.Equal(t, SendEventsResult{}, SendEvents[int](ctx, bus, nil))
+
+	// the events are retained one by one
+	retainSub := Subscribe[int](ctx, bus, OptionQueueSize(0), OptionOnOverflow(OnOverflowWait(0)))
+	defer retainSub.Finish(ctx)
+	go SendEvents(ctx, bus, []int{4, 5})
+	// the publisher is blocked on the event 4, thus 5 should not be retained yet
+	require.Eventually(t, func() bool {
+		retained, _ := bus.RetainedEvent(0)
+		return retained == 4
+	}, time.Second, time.Millisecond)
+	require.Equal(t, 4, <-retainSub.EventChan())
+	require.Equal(t, 5, <-retainSub.EventChan())
+
+	require.NoError(t, bus.Close(ctx))
+	r, err := SendEventsWithError(ctx, bus, []int{6})
+	require.ErrorIs(t, err, ErrBusClosed)
+	require.Equal(t, []SendEventResult{{}}, r.Events)
+
+	// the bus is closed in the middle of the batch
+	hook := &closingSendHook{closeOn: 8}
+	bus = New(BusOptionSendHook{hook})
+	hook.bus = bus
+	sub = Subscribe[int](ctx, bus, OptionQueueSize(3))
+	r, err = SendEventsWithError(ctx, bus, []int{7, 8})
+	require.ErrorIs(t, err, ErrBusClosed)
+	require.Equal(t, []SendEventResult{{}, {}}, r.Events)
+	_, ok = <-sub.EventChan()
+	require.False(t, ok)
+}
+
+type closingSendHook struct {
+	bus     *EventBus
+	closeOn int
+}
+
+func (h *closingSendHook) BeforeSend(ctx context.Context, topic, event any) context.Context {
+	if event == h.closeOn {
+		h.bus.Close(ctx)
+	}
+	return ctx
+}
+
+func (h *closingSendHook) AfterSend(ctx context.Context, topic, event any, result SendEventResult) {
+}
+
+func TestTopic(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	bus := New()
+
+	topic, err := GetTopicWithCustomTopic[string, int](ctx, bus, "numbers")
+	require.NoError(t, err)
+	require.Equal(t, "numbers", topic.Topic())
+	require.Equal(t, SendEventResult{}, topic.Send(ctx, 1))
+
+	sub := topic.Subscribe(ctx)
+	defer sub.Finish(ctx)
+	subWithCtx := SubscribeWithCustomTopicWithContext[string, int](ctx, bus, "numbers")
+	defer subWithCtx.Finish(ctx)
+	require.Equal(t, SendEventResult{SentCountImmediate: 2}, topic.Send(ctx, 2))
+	require.Equal(t, 2, <-sub.EventChan())
+	require.Equal(t, 2, (<-subWithCtx.EventChan()).Event)
+
+	// the handle sees the changes of the subscriptions
+	sub.Finish(ctx)
+	require.Equal(t, SendEventResult{SentCountImmediate: 1}, topic.Send(ctx, 3))
+	require.Equal(t, 3, (<-subWithCtx.EventChan()).Event)
+
+	// a type mismatch
+	_, err = GetTopicWithCustomTopic[string, string](ctx, bus, "numbers")
+	require.ErrorIs(t, err, ErrTopicTypeMismatch)
+}
+
+type subscribingSendHook struct {
+	bus  *EventBus
+	subs []*Subscription[string, int]
+}
+
+func (h *subscribingSendHook) BeforeSend(ctx context.Context, topic, event any) context.Context {
+	// e.g. a WAL subscriber subscribes right after the event is appended to the log
+	h.subs = append(h.subs, SubscribeWithCustomTopic[string, int](ctx, h.bus, "topic"))
+	return ctx
+}
+
+func (h *subscribingSendHook) AfterSend(ctx context.Context, topic, event any, result SendEventResult) {
+}
+
+func TestSendHooksOrder(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	hook := &subscribingSendHook{}
+	bus := New(BusOptionSendHook{hook})
+	hook.bus = bus
+	topic, err := GetTopicWithCustomTopic[string, int](ctx, bus, "topic")
+	require.NoError(t, err)
+
+	// the subscriptions are read after the hooks are called
+	require.Equal(t, SendEventResult{SentCountImmediate: 1}, SendEventWithCustomTopic(ctx, bus, "topic", 1))
+	require.Equal(t, 1, <-hook.subs[0].EventChan())
+	require.Equal(t, SendEventResult{SentCountImmediate: 2}, topic.Send(ctx, 2))
+	for _, sub := range hook.subs {
+		require.Equal(t, 2, <-sub.EventChan())
+	}
+	r, err := SendEventsWithCustomTopicWithError(ctx, bus, "topic", []int{3})
+	require.NoError(t, err)
+	require.Equal(t, SendEventResult{SentCountImmediate: 3}, r.Total)
+}
+
+func TestStrictTypes(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	bus := New(BusOptionStrictTypes(true))
+
+	_, ok := bus.TopicEventType("topic")
+	require.False(t, ok)
+
+	sub := SubscribeWithCustomTopic[string, int](ctx, bus, "topic")
+	require.NotNil(t, sub)
+	defer sub.Finish(ctx)
+	typ, ok := bus.TopicEventType("topic")
+	require.True(t, ok)
+	require.Equal(t, reflect.TypeFor[int](), typ)
+
+	subWithCtx := SubscribeWithCustomTopicWithContext[string, int](ctx, bus, "topic")
+	require.NotNil(t, subWithCtx)
+	defer subWithCtx.Finish(ctx)
+
+	require.Nil(t, SubscribeWithCustomTopic[string, string](ctx, bus, "topic"))
+	require.Equal(t, SendEventResult{}, SendEventWithCustomTopic(ctx, bus, "topic", "a string"))
+	_, err := GetTopicWithCustomTopic[string, string](ctx, bus, "topic")
+	require.ErrorIs(t, err, ErrTopicTypeMismatch)
+	require.Equal(t, SendEventResult{SentCountImmediate: 2}, SendEventWithCustomTopic(ctx, bus, "topic", 1))
+
+	// the first publishing binds the topic as well
+	require.Equal(t, SendEventResult{}, SendEvent(ctx, bus, uint(1)))
+	require.Nil(t, SubscribeWithCustomTopic[uint, int](ctx, bus, 0))
+
+	// the binding does not keep the topic state, and could be removed
+	require.Nil(t, bus.getTopicState(uint(0)))
+	bus.UnbindTopicEventType(uint(0))
+	_, ok = bus.TopicEventType(uint(0))
+	require.False(t, ok)
+	intSub := SubscribeWithCustomTopic[uint, int](ctx, bus, 0)
+	require.NotNil(t, intSub)
+	intSub.Finish(ctx)
+
+	debugBus := New(BusOptionStrictTypes(true), BusOptionPanicOnTypeMismatch(true))
+	SendEventWithCustomTopic(ctx, debugBus, "topic", 1)
+	require.Panics(t, func() {
+		SendEventWithCustomTopic(ctx, debugBus, "topic", "a string")
+	})
+}
+
+func TestErrors(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	canceledCtx, cancelCanceledFn := context.WithCancel(ctx)
+	cancelCanceledFn()
+	bus := New()
+
+	// subscribing
+	_, err := SubscribeWithError[int](ctx, bus, OptionOnSubscribed[int, string](func(context.Context, *Subscription[int, string]) {}))
+	require.ErrorIs(t, err, ErrCallbackTypeMismatch)
+	state := bus.getOrCreateTopicState(0)
+	require.True(t, state.Lock(ctx))
+	_, err = SubscribeWithError[int](canceledCtx, bus)
+	require.ErrorIs(t, err, ErrContextDone)
+	require.ErrorIs(t, err, context.Canceled)
+	state.Unlock()
+	sub, err := SubscribeWithError[int](ctx, bus)
+	require.NoError(t, err)
+
+	// publishing
+	r, err := SendEventWithError(canceledCtx, bus, 1)
+	require.ErrorIs(t, err, ErrContextDone)
+	require.Equal(t, SendEventResult{DropCountImmediate: 1}, r)
+	r, err = SendEventWithError(ctx, bus, 2)
+	require.NoError(t, err)
+	require.Equal(t, SendEventResult{SentCountImmediate: 1}, r)
+	require.Equal(t, 2, <-sub.EventChan())
+
+	// unsubscribing
+	require.NoError(t, sub.FinishWithError(ctx))
+	require.ErrorIs(t, UnsubscribeWithError(ctx, bus, sub), ErrNotSubscribed)
+
+	// closing
+	sub, err = SubscribeWithError[int](ctx, bus)
+	require.NoError(t, err)
+	eventChan := sub.EventChan()
+	require.NoError(t, bus.Close(ctx))
+	require.True(t, bus.IsClosed())
+	_, ok := <-eventChan
+	require.False(t, ok)
+	require.Empty(t, bus.Topics())
+	_, err = SubscribeWithError[int](ctx, bus)
+	require.ErrorIs(t, err, ErrBusClosed)
+	_, err = SendEventWithError(ctx, bus, 3)
+	require.ErrorIs(t, err, ErrBusClosed)
+	require.ErrorIs(t, bus.Close(ctx), ErrBusClosed)
+}
+
+func TestSubscriptionAll(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	bus := New()
+
+	// breaking out of the loop finishes the subscription
+	sub := Subscribe[int](ctx, bus, OptionQueueSize(3))
+	SendEvents(ctx, bus, []int{1, 2, 3})
+	var received []int
+	for ev := range sub.All(ctx) {
+		received = append(received, ev)
+		if ev == 2 {
+			break
+		}
+	}
+	require.Equal(t, []int{1, 2}, received)
+	require.Empty(t, bus.Topics())
+
+	// the context is done
+	sub = Subscribe[int](ctx, bus)
+	iterCtx, iterCancelFn := context.WithCancel(ctx)
+	iterCancelFn()
+	var lastErr error
+	for _, err := range sub.AllWithError(iterCtx) {
+		lastErr = err
+	}
+	require.ErrorIs(t, lastErr, ErrContextDone)
+	require.Empty(t, bus.Topics())
+
+	// an overflow
+	sub = Subscribe[int](ctx, bus, OptionQueueSize(1), OptionOnOverflow(OnOverflowClose{}))
+	SendEvents(ctx, bus, []int{1, 2})
+	received, lastErr = nil, nil
+	for ev, err := range sub.AllWithError(ctx) {
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		received = append(received, ev)
+	}
+	require.Equal(t, []int{1}, received)
+	require.ErrorIs(t, lastErr, ErrSubscriptionOverflow)
+
+	// the bus is closed
+	sub = Subscribe[int](ctx, bus)
+	go bus.Close(ctx)
+	lastErr = nil
+	for _, err := range sub.AllWithError(ctx) {
+		lastErr = err
+	}
+	require.ErrorIs(t, lastErr, ErrBusClosed)
+}
+
+func TestDeferredSend(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	bus := New()
+
+	var readers []*Subscription[int, int]
+	for range 10 {
+		sub := Subscribe[int](ctx, bus, OptionQueueSize(0), OptionOnOverflow(OnOverflowWait(0)))
+		defer sub.Finish(ctx)
+		readers = append(readers, sub)
+	}
+	dropSub := Subscribe[int](ctx, bus, OptionQueueSize(0), OptionOnOverflow(OnOverflowWait(10*time.Millisecond)))
+	defer dropSub.Finish(ctx)
+	closeSub := Subscribe[int](ctx, bus, OptionQueueSize(0), OptionOnOverflow(OnOverflowWaitOrClose(10*time.Millisecond)))
+	defer closeSub.Finish(ctx)
+
+	for _, sub := range readers {
+		go func() {
+			<-sub.EventChan()
+		}()
+	}
+	r := SendEvent(ctx, bus, 1)
+	require.Equal(t, SendEventResult{
+		SentCountDeferred: 10,
+		DropCountDeferred: 2,
+	}, r)
+	<-closeSub.Done()
+	require.Len(t, bus.AbstractSubscriptions(0), 11)
+}
+
+func TestWatchdog(t *testing.T) {
+	ctx := context.Background()
+	bus := New()
+	sub := Subscribe[int16](ctx, bus, OptionQueueSize(2), OptionOnOverflow(OnOverflowDrop{}))
+	defer sub.Finish(ctx)
+
+	var slowEvents []SlowConsumerEvent
+	watchdog := NewWatchdog(
+		bus,
+		SlowConsumerHookFunc(func(ctx context.Context, ev SlowConsumerEvent) {
+			slowEvents = append(slowEvents, ev)
+		}),
+		WatchdogOptionMaxBacklog(2),
+		WatchdogOptionMaxStall(0),
+	)
+
+	SendEvent[int16](ctx, bus, 1)
+	watchdog.Check(ctx)
+	require.Empty(t, slowEvents)
+
+	SendEvent[int16](ctx, bus, 2)
+	watchdog.Check(ctx)
+	require.Len(t, slowEvents, 1)
+	require.Equal(t, uint(2), slowEvents[0].Backlog)
+	require.Equal(t, int16(0), slowEvents[0].Topic)
+
+	watchdog.Check(ctx)
+	require.Len(t, slowEvents, 1, "the hook is supposed to be called once per episode")
+
+	<-sub.EventChan()
+	watchdog.Check(ctx)
+	SendEvent[int16](ctx, bus, 3)
+	watchdog.Check(ctx)
+	require.Len(t, slowEvents, 2)
+	require.Equal(t, uint64(1), slowEvents[1].Subscription.ReceivedCount())
+
+	// a slow subscriber of SlowConsumerEvent does not block the watchdog
+	eventSub := Subscribe[SlowConsumerEvent](ctx, bus, OptionQueueSize(0), OptionOnOverflow(OnOverflowWait(0)))
+	defer eventSub.Finish(ctx)
+	serveCtx, cancelServe := context.WithCancel(ctx)
+	defer cancelServe()
+	watchdog = NewWatchdog(bus, SlowConsumerSendEvent{Bus: bus}, WatchdogOptionMaxBacklog(2), WatchdogOptionMaxStall(0))
+	watchdog.Check(serveCtx)
+	watchdog.Check(serveCtx)
+	cancelServe()
+}
+
+func TestReceivedCount(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	bus := New()
+	sub := Subscribe[int](ctx, bus, OptionQueueSize(1), OptionOnOverflow(OnOverflowWait(0)))
+	defer sub.Finish(ctx)
+
+	go SendEvents(ctx, bus, make([]int, 1000))
+	for received := uint64(1); received <= 1000; received++ {
+		<-sub.EventChan()
+		// the event is counted before it is received
+		require.GreaterOrEqual(t, sub.ReceivedCount(), received)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	ctx := context.Background()
+	bus := New(BusOptionDiagnostics(true))
+	sub := Subscribe[int32](ctx, bus, OptionOnOverflow(OnOverflowWait(0)))
+	defer sub.Finish(ctx)
+	require.Contains(t, string(sub.CreatorStack()), "TestDiagnostics")
+
+	SendEvent[int32](ctx, bus, 1)
+	sendDone := make(chan SendEventResult)
+	go func() {
+		sendDone <- SendEvent[int32](ctx, bus, 2)
+	}()
+
+	var report DiagnosticsReport
+	require.Eventually(t, func() bool {
+		var ok bool
+		report, ok = bus.Diagnostics()
+		require.True(t, ok)
+		return len(report.BlockedPublishers) == 1
+	}, time.Second, time.Millisecond)
+	blocked := report.BlockedPublishers[0]
+	require.Equal(t, "int32", blocked.EventType)
+	require.Equal(t, sub.ID(), blocked.Subscription.ID)
+	require.Equal(t, uint(1), blocked.Subscription.Backlog)
+	require.Contains(t, blocked.PublisherStack, "TestDiagnostics")
+	require.Contains(t, blocked.Subscription.CreatorStack, "TestDiagnostics")
+
+	<-sub.EventChan()
+	require.Equal(t, SendEventResult{SentCountDeferred: 1}, <-sendDone)
+	report, _ = bus.Diagnostics()
+	require.Empty(t, report.BlockedPublishers)
+}
+
+type testSpillCodec struct{}
+
+func (testSpillCodec) MarshalEvent(event any) (string, []byte, error) {
+	b, err := json.Marshal(event)
+	return "uint16", b, err
+}
+
+func (testSpillCodec) UnmarshalEvent(typeName string, payload []byte) (any, error) {
+	var ev uint16
+	err := json.Unmarshal(payload, &ev)
+	return ev, err
+}
+
+func TestOnOverflowSpillToDisk(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	bus := New()
+	sub := Subscribe[uint16](ctx, bus, OptionOnOverflow(OnOverflowSpillToDisk(2, dir, testSpillCodec{})))
+	defer sub.Finish(ctx)
+
+	r := SendEvent[uint16](ctx, bus, 0)
+	require.Equal(t, SendEventResult{SentCountImmediate: 1}, r)
+	for i := uint16(1); i < 10; i++ {
+		r := SendEvent(ctx, bus, i)
+		require.Equal(t, SendEventResult{PiledCount: 1}, r)
+	}
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	require.Equal(t, uint(10), sub.Backlog())
+
+	for i := uint16(0); i < 10; i++ {
+		require.Equal(t, i, <-sub.EventChan())
+	}
+	require.Eventually(t, func() bool {
+		info, err := os.Stat(filepath.Join(dir, files[0].Name()))
+		require.NoError(t, err)
+		return info.Size() == 0 && sub.Backlog() == 0
+	}, time.Second, time.Millisecond)
+
+	sub.Finish(ctx)
+	require.Eventually(t, func() bool {
+		files, err := os.ReadDir(dir)
+		require.NoError(t, err)
+		return len(files) == 0
+	}, time.Second, time.Millisecond)
+
+	// a sending racing with the closing of the spill queue does not create a file
+	sub = Subscribe[uint16](ctx, bus, OptionOnOverflow(OnOverflowSpillToDisk(0, dir, testSpillCodec{})), OptionQueueSize(0))
+	defer sub.Finish(ctx)
+	sub.spill.Close()
+	require.Equal(t, sendEventToSubResultDropped, sub.sendEventSpilling(ctx, 1))
+	files, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
 func BenchmarkSendEvent(b *testing.B) {
 	ctx := context.Background()
 	for subCount := 0; subCount <= 1024; {
@@ -149,3 +831,96 @@ func BenchmarkSendEvent(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkSendEvents(b *testing.B) {
+	ctx := context.Background()
+	events := make([]int, 1000)
+	for _, subCount := range []int{1, 16} {
+		b.Run(fmt.Sprintf("subCount%d", subCount), func(b *testing.B) {
+			bus := New()
+			for range subCount {
+				Subscribe[int](ctx, bus, OptionOnOverflow(OnOverflowDrop{}))
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for b.Loop() {
+				SendEvents(ctx, bus, events)
+			}
+		})
+	}
+}
+
+func BenchmarkTopicSend(b *testing.B) {
+	ctx := context.Background()
+	for _, subCount := range []int{0, 1, 16} {
+		b.Run(fmt.Sprintf("subCount%d", subCount), func(b *testing.B) {
+			bus := New()
+			topic, err := GetTopic[int](ctx, bus)
+			require.NoError(b, err)
+			for range subCount {
+				topic.Subscribe(ctx, OptionOnOverflow(OnOverflowDrop{}))
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for b.Loop() {
+				topic.Send(ctx, 0)
+			}
+		})
+	}
+}
+
+func BenchmarkSendEventParallel(b *testing.B) {
+	ctx := context.Background()
+	for _, subCount := range []int{0, 1, 16} {
+		b.Run(fmt.Sprintf("sameTopic/subCount%d", subCount), func(b *testing.B) {
+			bus := New()
+			for range subCount {
+				Subscribe[int](ctx, bus, OptionOnOverflow(OnOverflowDrop{}))
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					SendEvent(ctx, bus, 0)
+				}
+			})
+		})
+		b.Run(fmt.Sprintf("differentTopics/subCount%d", subCount), func(b *testing.B) {
+			bus := New()
+			var lastTopic atomic.Int64
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				topic := lastTopic.Add(1)
+				for range subCount {
+					SubscribeWithCustomTopic[int64, int](ctx, bus, topic, OptionOnOverflow(OnOverflowDrop{}))
+				}
+				for pb.Next() {
+					SendEventWithCustomTopic(ctx, bus, topic, 0)
+				}
+			})
+		})
+	}
+}
+
+func BenchmarkSendEventDeferred(b *testing.B) {
+	ctx := context.Background()
+	for _, subCount := range []int{1, 16, 256} {
+		b.Run(fmt.Sprintf("subCount%d", subCount), func(b *testing.B) {
+			bus := New()
+			for range subCount {
+				sub := Subscribe[int](ctx, bus, OptionQueueSize(0), OptionOnOverflow(OnOverflowWait(0)))
+				defer sub.Finish(ctx)
+				go func() {
+					for range sub.EventChan() {
+					}
+				}()
+			}
+			b.ReportAllocs()
+			b.ResetTimer()
+			for b.Loop() {
+				SendEvent(ctx, bus, 0)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package eventbus
+
+import (
+	"context"
+)
+
+// EventWithContext is an event accompanied by a delivery context derived
+// from the context of the publisher (see ContextPropagator).
+type EventWithContext[E any] struct {
+	Context context.Context
+	Event   E
+}
+
+// SubscribeWithContext is the same as Subscribe, but the events
+// are received together with their delivery contexts.
+func SubscribeWithContext[E any](
+	ctx context.Context,
+	bus *EventBus,
+	opts ...Option,
+) *Subscription[E, EventWithContext[E]] {
+	var zeroValue E
+	return SubscribeWithCustomTopicWithContext[E, E](ctx, bus, zeroValue, opts...)
+}
+
+// SubscribeWithCustomTopicWithContext is the same as SubscribeWithCustomTopic,
+// but the events are received together with their delivery contexts.
+func SubscribeWithCustomTopicWithContext[T, E any](
+	ctx context.Context,
+	bus *EventBus,
+	topic T,
+	opts ...Option,
+) *Subscription[T, EventWithContext[E]] {
+	return SubscribeWithCustomTopic[T, EventWithContext[E]](ctx, bus, topic, opts...)
+}
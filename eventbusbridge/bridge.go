@@ -0,0 +1,281 @@
+// Package eventbusbridge connects EventBus instances of different processes
+// over stream sockets (TCP, Unix, etc), forwarding the selected topics.
+//
+// The events are serialized with the codec registry, so each forwarded
+// topic should be registered there (under the same name in all processes).
+//
+// Usage:
+//
+//	// process A:
+//	b := eventbusbridge.New(bus, reg)
+//	eventbusbridge.Export[MyEvent, MyEvent](b, MyEvent{})
+//	b.Import(OtherEvent{})
+//	err := b.Serve(ctx, listener)
+//
+//	// process B:
+//	b := eventbusbridge.New(bus, reg)
+//	eventbusbridge.Export[OtherEvent, OtherEvent](b, OtherEvent{})
+//	b.Import(MyEvent{})
+//	err := b.Dial(ctx, "unix", "/run/my-app.sock")
+package eventbusbridge
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/facebookincubator/go-belt/tool/logger"
+	"github.com/xaionaro-go/eventbus"
+	"github.com/xaionaro-go/eventbus/eventbuscodec"
+	"github.com/xaionaro-go/xcontext"
+)
+
+var (
+	ErrTopicNotRegistered = errors.New("the topic is not registered in the codec registry")
+)
+
+// Bridge forwards the exported topics of an EventBus to the remote peers,
+// and sends the events of the imported topics received from the remote
+// peers to the EventBus.
+//
+// An event received from a link is never exported back to the same link,
+// so it is safe to both export and import the same topic.
+type Bridge struct {
+	bus      *eventbus.EventBus
+	registry *eventbuscodec.Registry
+	config   config
+
+	locker  sync.Mutex
+	exports map[string]exportFunc
+	imports map[string]struct{}
+}
+
+// exportFunc starts forwarding a topic to the link; the returned function
+// stops it.
+type exportFunc func(ctx context.Context, l *link) (finish func(), err error)
+
+type originCtxKey struct{}
+
+// link is a single connection to a remote peer.
+type link struct {
+	conn        net.Conn
+	writeLocker sync.Mutex
+	encoder     *json.Encoder
+}
+
+func (l *link) write(env eventbuscodec.Envelope) error {
+	l.writeLocker.Lock()
+	defer l.writeLocker.Unlock()
+	return l.encoder.Encode(env)
+}
+
+// New returns a new Bridge of the EventBus.
+func New(
+	bus *eventbus.EventBus,
+	registry *eventbuscodec.Registry,
+	opts ...Option,
+) *Bridge {
+	return &Bridge{
+		bus:      bus,
+		registry: registry,
+		config:   Options(opts).Config(),
+		exports:  map[string]exportFunc{},
+		imports:  map[string]struct{}{},
+	}
+}
+
+// Export makes the Bridge to forward the events of the topic to the remote
+// peers. It affects only the links established afterwards.
+func Export[T, E any](
+	b *Bridge,
+	topic T,
+) error {
+	name, ok := b.registry.TopicName(topic)
+	if !ok {
+		return fmt.Errorf("%w: %#+v", ErrTopicNotRegistered, topic)
+	}
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	b.exports[name] = func(ctx context.Context, l *link) (func(), error) {
+		sub, err := eventbus.SubscribeWithCustomTopicWithError[T, eventbus.EventWithContext[E]](
+			ctx, b.bus, topic,
+			eventbus.OptionOnOverflow(b.config.linkOnOverflow),
+			eventbus.OptionQueueSize(b.config.linkQueueSize),
+			eventbus.OptionContextPropagator{ContextPropagator: eventbus.PropagateValues{originCtxKey{}}},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to subscribe to topic '%s': %w", name, err)
+		}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for ev := range sub.EventChan() {
+				if ev.Context.Value(originCtxKey{}) == l {
+					continue
+				}
+				env, err := b.registry.Encode(topic, ev.Event)
+				if err != nil {
+					logger.Errorf(ctx, "unable to encode an event of topic '%s': %v", name, err)
+					continue
+				}
+				if err := l.write(env); err != nil {
+					logger.Debugf(ctx, "unable to write to the link: %v", err)
+					l.conn.Close()
+				}
+			}
+		}()
+		return func() {
+			// ctx is usually done here (the link is closed)
+			sub.Finish(xcontext.DetachDone(ctx))
+			<-done
+		}, nil
+	}
+	return nil
+}
+
+// Import makes the Bridge to send the events of the topic received
+// from the remote peers to the EventBus.
+func (b *Bridge) Import(topic any) error {
+	name, ok := b.registry.TopicName(topic)
+	if !ok {
+		return fmt.Errorf("%w: %#+v", ErrTopicNotRegistered, topic)
+	}
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	b.imports[name] = struct{}{}
+	return nil
+}
+
+func (b *Bridge) getExports() []exportFunc {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	result := make([]exportFunc, 0, len(b.exports))
+	for _, export := range b.exports {
+		result = append(result, export)
+	}
+	return result
+}
+
+func (b *Bridge) isImported(topicName string) bool {
+	b.locker.Lock()
+	defer b.locker.Unlock()
+	_, ok := b.imports[topicName]
+	return ok
+}
+
+// ServeConn forwards the events over the connection until it is closed
+// or the context is cancelled. The connection is closed on return.
+//
+// It fails right away if an exported topic cannot be subscribed to
+// (e.g. eventbus.ErrTopicTypeMismatch or eventbus.ErrBusClosed).
+func (b *Bridge) ServeConn(
+	ctx context.Context,
+	conn net.Conn,
+) error {
+	ctx, cancelFn := context.WithCancel(ctx)
+	defer cancelFn()
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	l := &link{
+		conn:    conn,
+		encoder: json.NewEncoder(conn),
+	}
+	var finishers []func()
+	defer func() {
+		conn.Close()
+		for _, finish := range finishers {
+			finish()
+		}
+	}()
+	for _, export := range b.getExports() {
+		finish, err := export(ctx, l)
+		if err != nil {
+			return err
+		}
+		finishers = append(finishers, finish)
+	}
+
+	decoder := json.NewDecoder(bufio.NewReader(conn))
+	for {
+		var env eventbuscodec.Envelope
+		if err := decoder.Decode(&env); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("unable to read from the link: %w", err)
+		}
+		if !b.isImported(env.Topic) {
+			logger.Tracef(ctx, "topic '%s' is not imported, skipping", env.Topic)
+			continue
+		}
+		if _, err := b.registry.Send(context.WithValue(ctx, originCtxKey{}, l), b.bus, env); err != nil {
+			logger.Errorf(ctx, "unable to send an event of topic '%s': %v", env.Topic, err)
+		}
+	}
+}
+
+// Serve accepts the connections of the remote peers until the context
+// is cancelled or the listener fails.
+func (b *Bridge) Serve(
+	ctx context.Context,
+	listener net.Listener,
+) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancelFn := context.WithCancel(ctx)
+	defer cancelFn()
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("unable to accept a connection: %w", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := b.ServeConn(ctx, conn)
+			logger.Debugf(ctx, "the link with %s is closed: %v", conn.RemoteAddr(), err)
+		}()
+	}
+}
+
+// Dial connects to the remote peer and forwards the events, reconnecting
+// with backoff (see OptionReconnectBackoff) until the context is cancelled.
+func (b *Bridge) Dial(
+	ctx context.Context,
+	network string,
+	address string,
+) error {
+	backoff := b.config.reconnectBackoff.Min
+	for {
+		conn, err := b.config.dialer.DialContext(ctx, network, address)
+		if err == nil {
+			backoff = b.config.reconnectBackoff.Min
+			err = b.ServeConn(ctx, conn)
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		logger.Debugf(ctx, "the link with %s://%s is down: %v; reconnecting in %v", network, address, err, backoff)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, b.config.reconnectBackoff.Max)
+	}
+}
@@ -0,0 +1,128 @@
+package eventbusbridge
+
+import (
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xaionaro-go/eventbus"
+	"github.com/xaionaro-go/eventbus/eventbuscodec"
+)
+
+type testEvent struct {
+	Value int
+}
+
+func newTestBridge(t *testing.T) (*Bridge, *eventbus.EventBus) {
+	reg := eventbuscodec.NewRegistry()
+	require.NoError(t, eventbuscodec.RegisterEventType[testEvent](reg, "test-event", eventbuscodec.JSON{}))
+	bus := eventbus.New()
+	b := New(bus, reg, OptionReconnectBackoff{Min: time.Millisecond, Max: 10 * time.Millisecond})
+	require.NoError(t, Export[testEvent, testEvent](b, testEvent{}))
+	require.NoError(t, b.Import(testEvent{}))
+	return b, bus
+}
+
+// receiveForwarded sends events to the source bus until one of them
+// is received on the subscription (the link may be not established yet);
+// other events on the subscription are skipped.
+func receiveForwarded(
+	ctx context.Context,
+	t *testing.T,
+	src *eventbus.EventBus,
+	sub *eventbus.Subscription[testEvent, testEvent],
+	value int,
+) {
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		eventbus.SendEvent(ctx, src, testEvent{Value: value})
+		select {
+		case ev := <-sub.EventChan():
+			if ev.Value == value {
+				return
+			}
+		case <-ticker.C:
+		case <-ctx.Done():
+			t.Fatal(ctx.Err())
+		}
+	}
+}
+
+func TestBridge(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	sockPath := filepath.Join(t.TempDir(), "bridge.sock")
+
+	serverBridge, serverBus := newTestBridge(t)
+	clientBridge, clientBus := newTestBridge(t)
+	serverSub := eventbus.Subscribe[testEvent](ctx, serverBus, eventbus.OptionQueueSize(100))
+	defer serverSub.Finish(ctx)
+	clientSub := eventbus.Subscribe[testEvent](ctx, clientBus, eventbus.OptionQueueSize(100))
+	defer clientSub.Finish(ctx)
+
+	// dialing before listening to check the reconnection
+	dialErrCh := make(chan error, 1)
+	go func() { dialErrCh <- clientBridge.Dial(ctx, "unix", sockPath) }()
+	time.Sleep(10 * time.Millisecond)
+
+	listener, err := net.Listen("unix", sockPath)
+	require.NoError(t, err)
+	serveCtx, serveCancelFn := context.WithCancel(ctx)
+	serveErrCh := make(chan error, 1)
+	go func() { serveErrCh <- serverBridge.Serve(serveCtx, listener) }()
+
+	receiveForwarded(ctx, t, clientBus, serverSub, 1)
+	receiveForwarded(ctx, t, serverBus, clientSub, 2)
+
+	// the forwarded events are not echoed back
+	time.Sleep(50 * time.Millisecond) // letting the repeated sends to arrive
+	for len(clientSub.EventChan()) > 0 {
+		<-clientSub.EventChan()
+	}
+	for len(serverSub.EventChan()) > 0 {
+		<-serverSub.EventChan()
+	}
+	eventbus.SendEvent(ctx, clientBus, testEvent{Value: 3})
+	require.Equal(t, testEvent{Value: 3}, <-clientSub.EventChan())
+	require.Equal(t, testEvent{Value: 3}, <-serverSub.EventChan())
+	time.Sleep(50 * time.Millisecond)
+	require.Zero(t, len(clientSub.EventChan()))
+	require.Zero(t, len(serverSub.EventChan()))
+
+	// restarting the server to check the reconnection
+	serveCancelFn()
+	require.ErrorIs(t, <-serveErrCh, context.Canceled)
+	listener, err = net.Listen("unix", sockPath)
+	require.NoError(t, err)
+	go func() { serveErrCh <- serverBridge.Serve(ctx, listener) }()
+	receiveForwarded(ctx, t, clientBus, serverSub, 4)
+
+	cancelFn()
+	require.ErrorIs(t, <-dialErrCh, context.Canceled)
+	require.ErrorIs(t, <-serveErrCh, context.Canceled)
+}
+
+func TestBridgeExportFailure(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	reg := eventbuscodec.NewRegistry()
+	require.NoError(t, eventbuscodec.RegisterEventType[testEvent](reg, "test-event", eventbuscodec.JSON{}))
+	bus := eventbus.New(eventbus.BusOptionStrictTypes(true))
+	b := New(bus, reg)
+	require.NoError(t, Export[testEvent, testEvent](b, testEvent{}))
+
+	// binding the topic to another type
+	eventbus.SendEventWithCustomTopic(ctx, bus, testEvent{}, 1)
+	conn, peerConn := net.Pipe()
+	defer peerConn.Close()
+	require.ErrorIs(t, b.ServeConn(ctx, conn), eventbus.ErrTopicTypeMismatch)
+}
+
+func TestBridgeReconnectBackoff(t *testing.T) {
+	cfg := Options{OptionReconnectBackoff{}}.Config()
+	require.Equal(t, OptionReconnectBackoff{Min: minReconnectBackoff, Max: minReconnectBackoff}, cfg.reconnectBackoff)
+}
@@ -0,0 +1,91 @@
+package eventbusbridge
+
+import (
+	"net"
+	"time"
+
+	"github.com/xaionaro-go/eventbus"
+)
+
+type Option interface {
+	apply(*config)
+}
+
+type config struct {
+	linkOnOverflow   eventbus.OnOverflow
+	linkQueueSize    uint
+	reconnectBackoff OptionReconnectBackoff
+	dialer           *net.Dialer
+}
+
+type Options []Option
+
+func (s Options) Config() config {
+	cfg := config{
+		linkOnOverflow: eventbus.OnOverflowDrop{},
+		linkQueueSize:  1024,
+		reconnectBackoff: OptionReconnectBackoff{
+			Min: 100 * time.Millisecond,
+			Max: 10 * time.Second,
+		},
+		dialer: &net.Dialer{},
+	}
+	for _, opt := range s {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+type optionLinkOnOverflowT struct {
+	eventbus.OnOverflow
+}
+
+// OptionLinkOnOverflow defines what to do with the exported events
+// if the link cannot keep up (e.g. the network is slow or disconnected).
+//
+// The default is eventbus.OnOverflowDrop.
+func OptionLinkOnOverflow(v eventbus.OnOverflow) optionLinkOnOverflowT {
+	return optionLinkOnOverflowT{
+		OnOverflow: v,
+	}
+}
+
+func (opt optionLinkOnOverflowT) apply(cfg *config) {
+	cfg.linkOnOverflow = opt.OnOverflow
+}
+
+// OptionLinkQueueSize sets the size of the queue of exported events
+// (per topic per link). The default is 1024.
+type OptionLinkQueueSize uint
+
+func (opt OptionLinkQueueSize) apply(cfg *config) {
+	cfg.linkQueueSize = uint(opt)
+}
+
+// minReconnectBackoff is the floor of OptionReconnectBackoff.Min,
+// so that Dial never reconnects in a busy loop.
+const minReconnectBackoff = time.Millisecond
+
+// OptionReconnectBackoff sets the delays between the reconnection attempts
+// of Dial: it starts from Min and is doubled on each failure up to Max.
+//
+// Min is raised to 1ms if it is less than that, and Max to Min.
+type OptionReconnectBackoff struct {
+	Min time.Duration
+	Max time.Duration
+}
+
+func (opt OptionReconnectBackoff) apply(cfg *config) {
+	opt.Min = max(opt.Min, minReconnectBackoff)
+	opt.Max = max(opt.Max, opt.Min)
+	cfg.reconnectBackoff = opt
+}
+
+// OptionDialer sets the dialer used by Dial.
+type OptionDialer struct {
+	*net.Dialer
+}
+
+func (opt OptionDialer) apply(cfg *config) {
+	cfg.dialer = opt.Dialer
+}
@@ -0,0 +1,113 @@
+// Package eventbuscodec provides serialization of events and topics
+// of an EventBus, so that events could leave the process (for persistence
+// or networking).
+package eventbuscodec
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Codec is a serialization format of events.
+type Codec interface {
+	// Name is a stable identifier of the codec.
+	Name() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSON is a Codec using encoding/json.
+type JSON struct{}
+
+var _ Codec = JSON{}
+
+func (JSON) Name() string {
+	return "json"
+}
+
+func (JSON) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (JSON) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// Gob is a Codec using encoding/gob.
+type Gob struct{}
+
+var _ Codec = Gob{}
+
+func (Gob) Name() string {
+	return "gob"
+}
+
+func (Gob) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (Gob) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// CBOR is a Codec using github.com/fxamacker/cbor.
+type CBOR struct{}
+
+var _ Codec = CBOR{}
+
+func (CBOR) Name() string {
+	return "cbor"
+}
+
+func (CBOR) Marshal(v any) ([]byte, error) {
+	return cbor.Marshal(v)
+}
+
+func (CBOR) Unmarshal(data []byte, v any) error {
+	return cbor.Unmarshal(data, v)
+}
+
+// ProtoMarshaler is implemented by protobuf messages generated
+// by gogoproto, vtprotobuf and similar generators.
+type ProtoMarshaler interface {
+	Marshal() ([]byte, error)
+}
+
+// ProtoUnmarshaler is implemented by protobuf messages generated
+// by gogoproto, vtprotobuf and similar generators.
+type ProtoUnmarshaler interface {
+	Unmarshal([]byte) error
+}
+
+// Protobuf is a Codec for events implementing ProtoMarshaler and ProtoUnmarshaler.
+type Protobuf struct{}
+
+var _ Codec = Protobuf{}
+
+func (Protobuf) Name() string {
+	return "protobuf"
+}
+
+func (Protobuf) Marshal(v any) ([]byte, error) {
+	m, ok := v.(ProtoMarshaler)
+	if !ok {
+		return nil, fmt.Errorf("%T does not implement ProtoMarshaler", v)
+	}
+	return m.Marshal()
+}
+
+func (Protobuf) Unmarshal(data []byte, v any) error {
+	m, ok := v.(ProtoUnmarshaler)
+	if !ok {
+		return fmt.Errorf("%T does not implement ProtoUnmarshaler", v)
+	}
+	return m.Unmarshal(data)
+}
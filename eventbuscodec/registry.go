@@ -0,0 +1,332 @@
+package eventbuscodec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/xaionaro-go/eventbus"
+)
+
+var (
+	ErrAlreadyRegistered = errors.New("already registered")
+	ErrNotRegistered     = errors.New("not registered")
+	ErrCodecMismatch     = errors.New("codec mismatch")
+)
+
+// Envelope is a serialized event together with the identity of its topic.
+type Envelope struct {
+	Topic   string `json:"topic"`
+	Type    string `json:"type"`
+	Codec   string `json:"codec"`
+	Payload []byte `json:"payload"`
+}
+
+type eventType struct {
+	name   string
+	goType reflect.Type
+	codec  Codec
+}
+
+type topicEntry struct {
+	name      string
+	topic     any
+	eventType *eventType
+	send      func(ctx context.Context, bus *eventbus.EventBus, event any) eventbus.SendEventResult
+	subscribe func(ctx context.Context, bus *eventbus.EventBus, opts ...eventbus.Option) (*Subscription, error)
+}
+
+// Registry maps event types and topics to stable names and Codecs.
+//
+// Topics are arbitrary comparable values (and the zero value of the event
+// type for eventbus.Subscribe and eventbus.SendEvent), so each topic
+// that needs to leave the process should be registered with a name
+// (see RegisterTopic and RegisterEventType).
+type Registry struct {
+	locker        sync.RWMutex
+	typesByName   map[string]*eventType
+	typesByGoType map[reflect.Type]*eventType
+	topicsByName  map[string]*topicEntry
+	topicsByValue map[any]*topicEntry
+}
+
+var _ eventbus.SpillCodec = (*Registry)(nil)
+
+func NewRegistry() *Registry {
+	return &Registry{
+		typesByName:   map[string]*eventType{},
+		typesByGoType: map[reflect.Type]*eventType{},
+		topicsByName:  map[string]*topicEntry{},
+		topicsByValue: map[any]*topicEntry{},
+	}
+}
+
+// RegisterType registers the event type E under the given stable name
+// to be serialized with the given Codec.
+func RegisterType[E any](
+	reg *Registry,
+	name string,
+	codec Codec,
+) error {
+	goType := reflect.TypeFor[E]()
+	reg.locker.Lock()
+	defer reg.locker.Unlock()
+	if _, ok := reg.typesByName[name]; ok {
+		return fmt.Errorf("type name '%s': %w", name, ErrAlreadyRegistered)
+	}
+	if _, ok := reg.typesByGoType[goType]; ok {
+		return fmt.Errorf("type %v: %w", goType, ErrAlreadyRegistered)
+	}
+	t := &eventType{
+		name:   name,
+		goType: goType,
+		codec:  codec,
+	}
+	reg.typesByName[name] = t
+	reg.typesByGoType[goType] = t
+	return nil
+}
+
+// RegisterTopic registers the topic under the given stable name. The event
+// type E should be already registered (see RegisterType).
+func RegisterTopic[T, E any](
+	reg *Registry,
+	name string,
+	topic T,
+) error {
+	goType := reflect.TypeFor[E]()
+	reg.locker.Lock()
+	defer reg.locker.Unlock()
+	t, ok := reg.typesByGoType[goType]
+	if !ok {
+		return fmt.Errorf("type %v: %w", goType, ErrNotRegistered)
+	}
+	if _, ok := reg.topicsByName[name]; ok {
+		return fmt.Errorf("topic name '%s': %w", name, ErrAlreadyRegistered)
+	}
+	if _, ok := reg.topicsByValue[topic]; ok {
+		return fmt.Errorf("topic %#+v: %w", topic, ErrAlreadyRegistered)
+	}
+	entry := &topicEntry{
+		name:      name,
+		topic:     topic,
+		eventType: t,
+		send: func(ctx context.Context, bus *eventbus.EventBus, event any) eventbus.SendEventResult {
+			return eventbus.SendEventWithCustomTopic(ctx, bus, topic, event.(E))
+		},
+		subscribe: func(ctx context.Context, bus *eventbus.EventBus, opts ...eventbus.Option) (*Subscription, error) {
+			sub, err := eventbus.SubscribeWithCustomTopicWithError[T, E](ctx, bus, topic, opts...)
+			if err != nil {
+				return nil, err
+			}
+			return newSubscription(ctx, reg, topic, sub), nil
+		},
+	}
+	reg.topicsByName[name] = entry
+	reg.topicsByValue[topic] = entry
+	return nil
+}
+
+// RegisterEventType registers both the type E and the topic used by
+// eventbus.Subscribe[E] and eventbus.SendEvent[E] under the same name.
+func RegisterEventType[E any](
+	reg *Registry,
+	name string,
+	codec Codec,
+) error {
+	if err := RegisterType[E](reg, name, codec); err != nil {
+		return err
+	}
+	var zeroValue E
+	return RegisterTopic[E, E](reg, name, zeroValue)
+}
+
+// TypeName returns the registered name of the given Go type.
+func (reg *Registry) TypeName(goType reflect.Type) (string, bool) {
+	reg.locker.RLock()
+	defer reg.locker.RUnlock()
+	t, ok := reg.typesByGoType[goType]
+	if !ok {
+		return "", false
+	}
+	return t.name, true
+}
+
+// TopicName returns the registered name of the given topic.
+func (reg *Registry) TopicName(topic any) (string, bool) {
+	reg.locker.RLock()
+	defer reg.locker.RUnlock()
+	entry, ok := reg.topicsByValue[topic]
+	if !ok {
+		return "", false
+	}
+	return entry.name, true
+}
+
+// Topic returns the topic registered under the given name.
+func (reg *Registry) Topic(name string) (any, bool) {
+	reg.locker.RLock()
+	defer reg.locker.RUnlock()
+	entry, ok := reg.topicsByName[name]
+	if !ok {
+		return nil, false
+	}
+	return entry.topic, true
+}
+
+func (reg *Registry) getTypeByGoType(goType reflect.Type) (*eventType, error) {
+	reg.locker.RLock()
+	defer reg.locker.RUnlock()
+	t, ok := reg.typesByGoType[goType]
+	if !ok {
+		return nil, fmt.Errorf("type %v: %w", goType, ErrNotRegistered)
+	}
+	return t, nil
+}
+
+func (reg *Registry) getTypeByName(name string) (*eventType, error) {
+	reg.locker.RLock()
+	defer reg.locker.RUnlock()
+	t, ok := reg.typesByName[name]
+	if !ok {
+		return nil, fmt.Errorf("type name '%s': %w", name, ErrNotRegistered)
+	}
+	return t, nil
+}
+
+func (reg *Registry) getTopicByName(name string) (*topicEntry, error) {
+	reg.locker.RLock()
+	defer reg.locker.RUnlock()
+	entry, ok := reg.topicsByName[name]
+	if !ok {
+		return nil, fmt.Errorf("topic name '%s': %w", name, ErrNotRegistered)
+	}
+	return entry, nil
+}
+
+func (reg *Registry) getTopicByValue(topic any) (*topicEntry, error) {
+	reg.locker.RLock()
+	defer reg.locker.RUnlock()
+	entry, ok := reg.topicsByValue[topic]
+	if !ok {
+		return nil, fmt.Errorf("topic %#+v: %w", topic, ErrNotRegistered)
+	}
+	return entry, nil
+}
+
+// MarshalEvent serializes an event of a registered type.
+func (reg *Registry) MarshalEvent(event any) (typeName string, payload []byte, err error) {
+	t, err := reg.getTypeByGoType(reflect.TypeOf(event))
+	if err != nil {
+		return "", nil, err
+	}
+	// passing a pointer, because some codecs (e.g. Protobuf) require it
+	ptr := reflect.New(t.goType)
+	ptr.Elem().Set(reflect.ValueOf(event))
+	payload, err = t.codec.Marshal(ptr.Interface())
+	if err != nil {
+		return "", nil, fmt.Errorf("unable to serialize %v with codec '%s': %w", t.goType, t.codec.Name(), err)
+	}
+	return t.name, payload, nil
+}
+
+// UnmarshalEvent deserializes an event of a registered type.
+func (reg *Registry) UnmarshalEvent(typeName string, payload []byte) (any, error) {
+	t, err := reg.getTypeByName(typeName)
+	if err != nil {
+		return nil, err
+	}
+	return t.unmarshal(payload)
+}
+
+func (t *eventType) unmarshal(payload []byte) (any, error) {
+	ptr := reflect.New(t.goType)
+	if err := t.codec.Unmarshal(payload, ptr.Interface()); err != nil {
+		return nil, fmt.Errorf("unable to deserialize %v with codec '%s': %w", t.goType, t.codec.Name(), err)
+	}
+	return ptr.Elem().Interface(), nil
+}
+
+// Encode serializes an event of a registered topic.
+func (reg *Registry) Encode(topic any, event any) (Envelope, error) {
+	entry, err := reg.getTopicByValue(topic)
+	if err != nil {
+		return Envelope{}, err
+	}
+	typeName, payload, err := reg.MarshalEvent(event)
+	if err != nil {
+		return Envelope{}, err
+	}
+	if typeName != entry.eventType.name {
+		return Envelope{}, fmt.Errorf("topic '%s' expects type '%s', but received '%s'", entry.name, entry.eventType.name, typeName)
+	}
+	return Envelope{
+		Topic:   entry.name,
+		Type:    typeName,
+		Codec:   entry.eventType.codec.Name(),
+		Payload: payload,
+	}, nil
+}
+
+// Decode deserializes an Envelope. The returned event is of
+// the type registered for the topic.
+func (reg *Registry) Decode(env Envelope) (topic any, event any, err error) {
+	entry, err := reg.getTopicByName(env.Topic)
+	if err != nil {
+		return nil, nil, err
+	}
+	event, err = entry.decodeEvent(env)
+	if err != nil {
+		return nil, nil, err
+	}
+	return entry.topic, event, nil
+}
+
+func (entry *topicEntry) decodeEvent(env Envelope) (any, error) {
+	t := entry.eventType
+	if env.Type != t.name {
+		return nil, fmt.Errorf("topic '%s' expects type '%s', but received '%s'", entry.name, t.name, env.Type)
+	}
+	if env.Codec != t.codec.Name() {
+		return nil, fmt.Errorf("%w: type '%s' uses codec '%s', but received '%s'", ErrCodecMismatch, t.name, t.codec.Name(), env.Codec)
+	}
+	return t.unmarshal(env.Payload)
+}
+
+// Send decodes the Envelope and sends the event to the bus.
+func (reg *Registry) Send(
+	ctx context.Context,
+	bus *eventbus.EventBus,
+	env Envelope,
+) (eventbus.SendEventResult, error) {
+	entry, err := reg.getTopicByName(env.Topic)
+	if err != nil {
+		return eventbus.SendEventResult{}, err
+	}
+	event, err := entry.decodeEvent(env)
+	if err != nil {
+		return eventbus.SendEventResult{}, err
+	}
+	return entry.send(ctx, bus, event), nil
+}
+
+// Subscribe subscribes to the topic registered under the given name;
+// the events are received serialized (see Subscription).
+func (reg *Registry) Subscribe(
+	ctx context.Context,
+	bus *eventbus.EventBus,
+	topicName string,
+	opts ...eventbus.Option,
+) (*Subscription, error) {
+	entry, err := reg.getTopicByName(topicName)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := entry.subscribe(ctx, bus, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("unable to subscribe to topic '%s': %w", topicName, err)
+	}
+	return sub, nil
+}
@@ -0,0 +1,90 @@
+package eventbuscodec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xaionaro-go/eventbus"
+)
+
+type testEvent struct {
+	Value int
+	Text  string
+}
+
+type testProtoEvent struct {
+	Value byte
+}
+
+func (ev *testProtoEvent) Marshal() ([]byte, error) {
+	return []byte{ev.Value}, nil
+}
+
+func (ev *testProtoEvent) Unmarshal(b []byte) error {
+	ev.Value = b[0]
+	return nil
+}
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	for _, codec := range []Codec{JSON{}, Gob{}, CBOR{}} {
+		t.Run(codec.Name(), func(t *testing.T) {
+			reg := NewRegistry()
+			require.NoError(t, RegisterEventType[testEvent](reg, "test-event", codec))
+			require.NoError(t, RegisterTopic[string, testEvent](reg, "custom", "my-custom-topic"))
+			require.ErrorIs(t, RegisterType[testEvent](reg, "other-name", codec), ErrAlreadyRegistered)
+			require.ErrorIs(t, RegisterTopic[string, int](reg, "ints", "ints"), ErrNotRegistered)
+
+			env, err := reg.Encode("my-custom-topic", testEvent{Value: 1, Text: "a"})
+			require.NoError(t, err)
+			require.Equal(t, "custom", env.Topic)
+			require.Equal(t, "test-event", env.Type)
+			require.Equal(t, codec.Name(), env.Codec)
+
+			topic, event, err := reg.Decode(env)
+			require.NoError(t, err)
+			require.Equal(t, "my-custom-topic", topic)
+			require.Equal(t, testEvent{Value: 1, Text: "a"}, event)
+
+			bus := eventbus.New()
+			sub := eventbus.Subscribe[testEvent](ctx, bus)
+			defer sub.Finish(ctx)
+			env, err = reg.Encode(testEvent{}, testEvent{Value: 2})
+			require.NoError(t, err)
+			require.Equal(t, "test-event", env.Topic)
+			r, err := reg.Send(ctx, bus, env)
+			require.NoError(t, err)
+			require.Equal(t, eventbus.SendEventResult{SentCountImmediate: 1}, r)
+			require.Equal(t, testEvent{Value: 2}, <-sub.EventChan())
+
+			env.Codec = "unknown"
+			_, _, err = reg.Decode(env)
+			require.ErrorIs(t, err, ErrCodecMismatch)
+
+			// finishing after the context of the subscribing is done
+			subCtx, cancelSubCtx := context.WithCancel(ctx)
+			codecSub, err := reg.Subscribe(subCtx, bus, "test-event")
+			require.NoError(t, err)
+			cancelSubCtx()
+			codecSub.Finish()
+			require.Len(t, bus.AbstractSubscriptions(testEvent{}), 1)
+
+			closedBus := eventbus.New()
+			require.NoError(t, closedBus.Close(ctx))
+			_, err = reg.Subscribe(ctx, closedBus, "test-event")
+			require.ErrorIs(t, err, eventbus.ErrBusClosed)
+		})
+	}
+
+	t.Run("protobuf", func(t *testing.T) {
+		reg := NewRegistry()
+		require.NoError(t, RegisterEventType[testProtoEvent](reg, "proto-event", Protobuf{}))
+		env, err := reg.Encode(testProtoEvent{}, testProtoEvent{Value: 3})
+		require.NoError(t, err)
+		require.Equal(t, []byte{3}, env.Payload)
+		_, event, err := reg.Decode(env)
+		require.NoError(t, err)
+		require.Equal(t, testProtoEvent{Value: 3}, event)
+	})
+}
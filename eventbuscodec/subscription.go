@@ -0,0 +1,73 @@
+package eventbuscodec
+
+import (
+	"context"
+	"sync"
+
+	"github.com/facebookincubator/go-belt/tool/logger"
+	"github.com/xaionaro-go/eventbus"
+	"github.com/xaionaro-go/xcontext"
+)
+
+// Subscription is a subscription to a registered topic with
+// the events serialized as Envelopes (see Registry.Subscribe).
+type Subscription struct {
+	eventChan  chan Envelope
+	done       chan struct{}
+	finishOnce sync.Once
+	finish     func()
+	err        func() error
+}
+
+func newSubscription[T, E any](
+	ctx context.Context,
+	reg *Registry,
+	topic T,
+	sub *eventbus.Subscription[T, E],
+) *Subscription {
+	s := &Subscription{
+		eventChan: make(chan Envelope),
+		done:      make(chan struct{}),
+		finish: func() {
+			// ctx (of the subscribing) might be already done
+			sub.Finish(xcontext.DetachDone(ctx))
+		},
+		err: sub.Err,
+	}
+	go func() {
+		defer close(s.eventChan)
+		for ev := range sub.EventChan() {
+			env, err := reg.Encode(topic, ev)
+			if err != nil {
+				logger.Errorf(ctx, "unable to encode an event of topic %#+v: %v", topic, err)
+				continue
+			}
+			select {
+			case s.eventChan <- env:
+			case <-s.done:
+				// draining until the subscription is closed
+			}
+		}
+	}()
+	return s
+}
+
+// EventChan returns the channel of the serialized events. It is closed
+// when the subscription is finished (see Finish and eventbus.OnOverflowClose).
+func (s *Subscription) EventChan() <-chan Envelope {
+	return s.eventChan
+}
+
+// Err returns the reason the subscription was closed by the EventBus
+// (see eventbus.Subscription.Err).
+func (s *Subscription) Err() error {
+	return s.err()
+}
+
+// Finish unsubscribes from the topic.
+func (s *Subscription) Finish() {
+	s.finishOnce.Do(func() {
+		close(s.done)
+		s.finish()
+	})
+}
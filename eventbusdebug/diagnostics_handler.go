@@ -0,0 +1,43 @@
+// Package eventbusdebug provides HTTP handlers for inspecting an EventBus.
+package eventbusdebug
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/xaionaro-go/eventbus"
+)
+
+// DiagnosticsHandler is an http.Handler that dumps the graph of publishers
+// blocked on subscriptions (see eventbus.BusOptionDiagnostics).
+//
+// The dump is in JSON, or in plain text if "?format=text" is requested.
+type DiagnosticsHandler struct {
+	Bus *eventbus.EventBus
+}
+
+var _ http.Handler = (*DiagnosticsHandler)(nil)
+
+func NewDiagnosticsHandler(bus *eventbus.EventBus) *DiagnosticsHandler {
+	return &DiagnosticsHandler{
+		Bus: bus,
+	}
+}
+
+func (h *DiagnosticsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	report, ok := h.Bus.Diagnostics()
+	if !ok {
+		http.Error(w, "diagnostics are not enabled, see eventbus.BusOptionDiagnostics", http.StatusNotFound)
+		return
+	}
+	switch r.URL.Query().Get("format") {
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		report.WriteTo(w)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.Encode(report)
+	}
+}
@@ -0,0 +1,139 @@
+package eventbusdebug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/xaionaro-go/eventbus"
+)
+
+// Formatter converts an event to the text to be sent to the
+// server-sent-events stream.
+type Formatter[E any] func(E) (string, error)
+
+// FormatJSON is a Formatter that encodes the event in JSON.
+func FormatJSON[E any](ev E) (string, error) {
+	b, err := json.Marshal(ev)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// FormatGo is a Formatter that formats the event using "%#+v".
+func FormatGo[E any](ev E) (string, error) {
+	return fmt.Sprintf("%#+v", ev), nil
+}
+
+// StreamQueueSize is the queue size of the subscriptions used
+// to stream events (see AddTopic).
+const StreamQueueSize = 16
+
+// AddTopic allows to stream the events of the given topic under the given
+// name (see "/events?topic=<name>"). If format is nil, then FormatJSON is used.
+//
+// The events are received using OnOverflowDrop, so a slow HTTP client
+// does not affect publishers.
+func AddTopic[T, E any](
+	h *Handler,
+	name string,
+	topic T,
+	format Formatter[E],
+) {
+	if format == nil {
+		format = FormatJSON[E]
+	}
+	h.topicsLocker.Lock()
+	defer h.topicsLocker.Unlock()
+	h.topics[name] = func(ctx context.Context, w http.ResponseWriter, flusher http.Flusher) {
+		sub := eventbus.SubscribeWithCustomTopic[T, E](
+			ctx, h.Bus, topic,
+			eventbus.OptionQueueSize(StreamQueueSize),
+			eventbus.OptionOnOverflow(eventbus.OnOverflowDrop{}),
+		)
+		if sub == nil {
+			return
+		}
+		defer sub.Finish(context.Background())
+		flusher.Flush()
+		for {
+			var (
+				ev E
+				ok bool
+			)
+			select {
+			case <-ctx.Done():
+				return
+			case <-sub.Done():
+				return
+			case ev, ok = <-sub.EventChan():
+				if !ok {
+					return
+				}
+			}
+			text, err := format(ev)
+			if err != nil {
+				fmt.Fprintf(w, "event: error\n%s\n", sseData(err.Error()))
+			} else {
+				fmt.Fprintf(w, "%s\n", sseData(text))
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// AddEventType is the same as AddTopic, but for topics used by
+// eventbus.SendEvent and eventbus.Subscribe.
+func AddEventType[E any](
+	h *Handler,
+	name string,
+	format Formatter[E],
+) {
+	var zeroValue E
+	AddTopic(h, name, zeroValue, format)
+}
+
+func sseData(text string) string {
+	var buf strings.Builder
+	for _, line := range strings.Split(text, "\n") {
+		buf.WriteString("data: ")
+		buf.WriteString(line)
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+func (h *Handler) streamableTopics() []string {
+	h.topicsLocker.Lock()
+	defer h.topicsLocker.Unlock()
+	result := make([]string, 0, len(h.topics))
+	for name := range h.topics {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (h *Handler) serveEvents(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("topic")
+	h.topicsLocker.Lock()
+	stream := h.topics[name]
+	h.topicsLocker.Unlock()
+	if stream == nil {
+		http.Error(w, fmt.Sprintf("unknown topic %q, available: %v", name, h.streamableTopics()), http.StatusNotFound)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	stream(r.Context(), w, flusher)
+}
@@ -0,0 +1,192 @@
+package eventbusdebug
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/xaionaro-go/eventbus"
+)
+
+// SubscriptionState is the state of a subscription.
+type SubscriptionState struct {
+	ID             uint64 `json:"id"`
+	Backlog        uint   `json:"backlog"`
+	QueueSize      uint   `json:"queue_size"`
+	DeliveredCount uint64 `json:"delivered_count"`
+	ReceivedCount  uint64 `json:"received_count"`
+	OnOverflow     string `json:"on_overflow"`
+}
+
+// TopicState is the state of a topic.
+type TopicState struct {
+	Topic         string              `json:"topic"`
+	Stats         *TopicStats         `json:"stats,omitempty"`
+	Subscriptions []SubscriptionState `json:"subscriptions"`
+}
+
+// BusState is the state of an EventBus.
+type BusState struct {
+	Topics []TopicState `json:"topics"`
+}
+
+// Handler is an http.Handler to inspect an EventBus:
+//
+//	/             -- an HTML page with the state of the bus;
+//	/state        -- the state of the bus in JSON;
+//	/events?topic= -- a server-sent-events stream of the events of a topic (see AddTopic);
+//	/diagnostics  -- see DiagnosticsHandler.
+type Handler struct {
+	Bus   *eventbus.EventBus
+	Stats *Stats
+
+	mux          *http.ServeMux
+	topicsLocker sync.Mutex
+	topics       map[string]topicStreamer
+}
+
+var _ http.Handler = (*Handler)(nil)
+
+type topicStreamer func(ctx context.Context, w http.ResponseWriter, flusher http.Flusher)
+
+// NewHandler returns a new Handler. The stats are optional
+// (they are collected only if Stats is passed to eventbus.New as a SendHook).
+func NewHandler(
+	bus *eventbus.EventBus,
+	stats *Stats,
+) *Handler {
+	h := &Handler{
+		Bus:    bus,
+		Stats:  stats,
+		mux:    http.NewServeMux(),
+		topics: map[string]topicStreamer{},
+	}
+	h.mux.HandleFunc("/", h.serveHTML)
+	h.mux.HandleFunc("/state", h.serveState)
+	h.mux.HandleFunc("/events", h.serveEvents)
+	h.mux.Handle("/diagnostics", NewDiagnosticsHandler(bus))
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// State returns the current state of the bus.
+func (h *Handler) State(ctx context.Context) BusState {
+	var stats map[any]TopicStats
+	if h.Stats != nil {
+		stats = h.Stats.Topics()
+	}
+
+	topics := map[string]*TopicState{}
+	getTopic := func(topic any) *TopicState {
+		name := topicName(topic)
+		state := topics[name]
+		if state == nil {
+			state = &TopicState{
+				Topic:         name,
+				Subscriptions: []SubscriptionState{},
+			}
+			topics[name] = state
+		}
+		return state
+	}
+	for topic, topicStats := range stats {
+		getTopic(topic).Stats = &topicStats
+	}
+	for _, topic := range h.Bus.Topics() {
+		state := getTopic(topic)
+		for _, sub := range h.Bus.AbstractSubscriptions(topic) {
+			state.Subscriptions = append(state.Subscriptions, SubscriptionState{
+				ID:             sub.ID(),
+				Backlog:        sub.Backlog(),
+				QueueSize:      sub.QueueSize(),
+				DeliveredCount: sub.DeliveredCount(),
+				ReceivedCount:  sub.ReceivedCount(),
+				OnOverflow:     fmt.Sprintf("%#+v", sub.OnOverflow()),
+			})
+		}
+		sort.Slice(state.Subscriptions, func(i, j int) bool {
+			return state.Subscriptions[i].ID < state.Subscriptions[j].ID
+		})
+	}
+
+	result := BusState{
+		Topics: make([]TopicState, 0, len(topics)),
+	}
+	for _, state := range topics {
+		result.Topics = append(result.Topics, *state)
+	}
+	sort.Slice(result.Topics, func(i, j int) bool {
+		return result.Topics[i].Topic < result.Topics[j].Topic
+	})
+	return result
+}
+
+func topicName(topic any) string {
+	return fmt.Sprintf("%#+v", topic)
+}
+
+func (h *Handler) serveState(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(h.State(r.Context()))
+}
+
+var htmlTemplate = template.Must(template.New("").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta http-equiv="refresh" content="5">
+<title>eventbus</title>
+<style>
+table { border-collapse: collapse; margin-bottom: 1em; }
+td, th { border: 1px solid #999; padding: 0.2em 0.5em; }
+</style>
+</head>
+<body>
+<p><a href="state">JSON</a> | <a href="diagnostics?format=text">diagnostics</a></p>
+{{range .State.Topics}}
+<h3>{{.Topic}}</h3>
+{{with .Stats}}
+<p>sends: {{.SendCount}}; sent immediately: {{.SentCountImmediate}}; sent deferred: {{.SentCountDeferred}}; piled: {{.PiledCount}}; dropped immediately: {{.DropCountImmediate}}; dropped deferred: {{.DropCountDeferred}}</p>
+{{end}}
+{{with .Subscriptions}}
+<table>
+<tr><th>ID</th><th>backlog</th><th>queue size</th><th>delivered</th><th>received</th><th>on overflow</th></tr>
+{{range .}}<tr><td>{{.ID}}</td><td>{{.Backlog}}</td><td>{{.QueueSize}}</td><td>{{.DeliveredCount}}</td><td>{{.ReceivedCount}}</td><td>{{.OnOverflow}}</td></tr>
+{{end}}
+</table>
+{{end}}
+{{end}}
+{{with .StreamableTopics}}
+<h3>Live events</h3>
+<ul>
+{{range .}}<li><a href="events?topic={{.}}">{{.}}</a></li>
+{{end}}
+</ul>
+{{end}}
+</body>
+</html>
+`))
+
+func (h *Handler) serveHTML(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	htmlTemplate.Execute(w, struct {
+		State            BusState
+		StreamableTopics []string
+	}{
+		State:            h.State(r.Context()),
+		StreamableTopics: h.streamableTopics(),
+	})
+}
@@ -0,0 +1,59 @@
+package eventbusdebug
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xaionaro-go/eventbus"
+)
+
+type testEvent struct {
+	Value int
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	stats := NewStats()
+	bus := eventbus.New(eventbus.BusOptionSendHook{SendHook: stats})
+	h := NewHandler(bus, stats)
+	AddEventType[testEvent](h, "test", nil)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	sub := eventbus.Subscribe[testEvent](ctx, bus, eventbus.OptionQueueSize(2))
+	defer sub.Finish(ctx)
+	eventbus.SendEvent(ctx, bus, testEvent{Value: 1})
+
+	resp, err := http.Get(srv.URL + "/state")
+	require.NoError(t, err)
+	var state BusState
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&state))
+	resp.Body.Close()
+	require.Len(t, state.Topics, 1)
+	require.Equal(t, uint64(1), state.Topics[0].Stats.SendCount)
+	require.Len(t, state.Topics[0].Subscriptions, 1)
+	require.Equal(t, uint(1), state.Topics[0].Subscriptions[0].Backlog)
+
+	resp, err = http.Get(srv.URL + "/")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = http.Get(srv.URL + "/events?topic=test")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	require.Eventually(t, func() bool {
+		return len(bus.AbstractSubscriptions(testEvent{})) == 2
+	}, time.Second, time.Millisecond)
+	eventbus.SendEvent(ctx, bus, testEvent{Value: 2})
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "data: {\"Value\":2}\n", line)
+}
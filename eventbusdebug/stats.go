@@ -0,0 +1,74 @@
+package eventbusdebug
+
+import (
+	"context"
+	"sync"
+
+	"github.com/xaionaro-go/eventbus"
+)
+
+// TopicStats is the accumulated statistics of sending events to a topic.
+type TopicStats struct {
+	SendCount          uint64 `json:"send_count"`
+	SentCountImmediate uint64 `json:"sent_count_immediate"`
+	SentCountDeferred  uint64 `json:"sent_count_deferred"`
+	PiledCount         uint64 `json:"piled_count"`
+	DropCountImmediate uint64 `json:"drop_count_immediate"`
+	DropCountDeferred  uint64 `json:"drop_count_deferred"`
+}
+
+// Stats is an eventbus.SendHook that collects TopicStats.
+//
+// Usage:
+//
+//	stats := eventbusdebug.NewStats()
+//	bus := eventbus.New(eventbus.BusOptionSendHook{SendHook: stats})
+type Stats struct {
+	locker sync.Mutex
+	topics map[any]*TopicStats
+}
+
+var _ eventbus.SendHook = (*Stats)(nil)
+
+func NewStats() *Stats {
+	return &Stats{
+		topics: map[any]*TopicStats{},
+	}
+}
+
+// BeforeSend implements eventbus.SendHook.
+func (s *Stats) BeforeSend(ctx context.Context, topic, event any) context.Context {
+	return ctx
+}
+
+// AfterSend implements eventbus.SendHook.
+func (s *Stats) AfterSend(
+	ctx context.Context,
+	topic, event any,
+	result eventbus.SendEventResult,
+) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	stats := s.topics[topic]
+	if stats == nil {
+		stats = &TopicStats{}
+		s.topics[topic] = stats
+	}
+	stats.SendCount++
+	stats.SentCountImmediate += uint64(result.SentCountImmediate)
+	stats.SentCountDeferred += uint64(result.SentCountDeferred)
+	stats.PiledCount += uint64(result.PiledCount)
+	stats.DropCountImmediate += uint64(result.DropCountImmediate)
+	stats.DropCountDeferred += uint64(result.DropCountDeferred)
+}
+
+// Topics returns a copy of the statistics of all topics an event was ever sent to.
+func (s *Stats) Topics() map[any]TopicStats {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	result := make(map[any]TopicStats, len(s.topics))
+	for topic, stats := range s.topics {
+		result[topic] = *stats
+	}
+	return result
+}
@@ -0,0 +1,238 @@
+// Package eventbusgateway lets browser clients subscribe to and publish
+// to the allowed topics of an EventBus over WebSocket or Server-Sent Events,
+// with the events encoded in JSON.
+//
+// Endpoints:
+//
+//	/ws                    -- a WebSocket connection (see Message);
+//	/sse?topic=<name>&...  -- a server-sent-events stream of the events of the topics
+//	                          (the SSE event type is the topic name);
+//	/publish?topic=<name>  -- publishes the event in the POST request body.
+//
+// Usage:
+//
+//	gw := eventbusgateway.New(bus, eventbusgateway.OptionAuthorizer(myAuthorizer))
+//	eventbusgateway.AllowSubscribe[Price, Price](gw, "prices", Price{})
+//	eventbusgateway.AllowPublish[Order, Order](gw, "orders", Order{})
+//	http.Handle("/bus/", http.StripPrefix("/bus", gw))
+package eventbusgateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/facebookincubator/go-belt/tool/logger"
+	"github.com/xaionaro-go/eventbus"
+	"github.com/xaionaro-go/xcontext"
+)
+
+var (
+	ErrTopicNotAllowed = errors.New("the topic is not allowed")
+)
+
+// Access is a kind of access to a topic.
+type Access int
+
+const (
+	AccessUndefined = Access(iota)
+	AccessSubscribe
+	AccessPublish
+)
+
+func (a Access) String() string {
+	switch a {
+	case AccessUndefined:
+		return "undefined"
+	case AccessSubscribe:
+		return "subscribe"
+	case AccessPublish:
+		return "publish"
+	default:
+		return fmt.Sprintf("unknown_%d", int(a))
+	}
+}
+
+// Authorizer decides if the request may access the topic
+// (a non-nil error denies the access).
+type Authorizer func(r *http.Request, access Access, topicName string) error
+
+// sink is a client connection to send events to.
+type sink interface {
+	sendEvent(ctx context.Context, topicName string, event json.RawMessage) error
+	abort(err error)
+}
+
+type subscribeFunc func(ctx context.Context, s sink) (finish func())
+
+type publishFunc func(ctx context.Context, event json.RawMessage) error
+
+// Gateway is an http.Handler that gives the clients access to the allowed
+// topics of an EventBus.
+type Gateway struct {
+	bus          *eventbus.EventBus
+	config       config
+	mux          *http.ServeMux
+	topicsLocker sync.Mutex
+	subscribable map[string]subscribeFunc
+	publishable  map[string]publishFunc
+}
+
+var _ http.Handler = (*Gateway)(nil)
+
+// New returns a new Gateway of the EventBus. No topic is accessible
+// until allowed (see AllowSubscribe and AllowPublish).
+func New(
+	bus *eventbus.EventBus,
+	opts ...Option,
+) *Gateway {
+	g := &Gateway{
+		bus:          bus,
+		config:       Options(opts).Config(),
+		mux:          http.NewServeMux(),
+		subscribable: map[string]subscribeFunc{},
+		publishable:  map[string]publishFunc{},
+	}
+	g.mux.HandleFunc("/ws", g.serveWebSocket)
+	g.mux.HandleFunc("/sse", g.serveSSE)
+	g.mux.HandleFunc("/publish", g.servePublish)
+	return g
+}
+
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+// AllowSubscribe allows the clients to subscribe to the topic
+// under the given name.
+func AllowSubscribe[T, E any](
+	g *Gateway,
+	name string,
+	topic T,
+) {
+	g.topicsLocker.Lock()
+	defer g.topicsLocker.Unlock()
+	g.subscribable[name] = func(ctx context.Context, s sink) func() {
+		sub := eventbus.SubscribeWithCustomTopic[T, E](
+			ctx, g.bus, topic,
+			eventbus.OptionOnOverflow(g.config.onOverflow),
+			eventbus.OptionQueueSize(g.config.queueSize),
+		)
+		if sub == nil {
+			return func() {}
+		}
+		var finished atomic.Bool
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for ev := range sub.EventChan() {
+				b, err := json.Marshal(ev)
+				if err != nil {
+					logger.Errorf(ctx, "unable to serialize an event of topic '%s': %v", name, err)
+					continue
+				}
+				if err := s.sendEvent(ctx, name, b); err != nil {
+					s.abort(err)
+					return
+				}
+			}
+			if !finished.Load() {
+				s.abort(fmt.Errorf("the subscription to topic '%s' is closed, the client is too slow", name))
+			}
+		}()
+		return func() {
+			finished.Store(true)
+			// ctx is usually done here (the client has disconnected)
+			sub.Finish(xcontext.DetachDone(ctx))
+			<-done
+		}
+	}
+}
+
+// AllowPublish allows the clients to publish to the topic
+// under the given name.
+func AllowPublish[T, E any](
+	g *Gateway,
+	name string,
+	topic T,
+) {
+	g.topicsLocker.Lock()
+	defer g.topicsLocker.Unlock()
+	g.publishable[name] = func(ctx context.Context, payload json.RawMessage) error {
+		var ev E
+		if err := json.Unmarshal(payload, &ev); err != nil {
+			return fmt.Errorf("unable to parse an event of topic '%s': %w", name, err)
+		}
+		eventbus.SendEventWithCustomTopic(ctx, g.bus, topic, ev)
+		return nil
+	}
+}
+
+func (g *Gateway) getSubscribe(r *http.Request, name string) (subscribeFunc, error) {
+	g.topicsLocker.Lock()
+	subscribe := g.subscribable[name]
+	g.topicsLocker.Unlock()
+	if subscribe == nil {
+		return nil, fmt.Errorf("%w: '%s'", ErrTopicNotAllowed, name)
+	}
+	if err := g.authorize(r, AccessSubscribe, name); err != nil {
+		return nil, err
+	}
+	return subscribe, nil
+}
+
+func (g *Gateway) getPublish(r *http.Request, name string) (publishFunc, error) {
+	g.topicsLocker.Lock()
+	publish := g.publishable[name]
+	g.topicsLocker.Unlock()
+	if publish == nil {
+		return nil, fmt.Errorf("%w: '%s'", ErrTopicNotAllowed, name)
+	}
+	if err := g.authorize(r, AccessPublish, name); err != nil {
+		return nil, err
+	}
+	return publish, nil
+}
+
+func (g *Gateway) authorize(r *http.Request, access Access, name string) error {
+	if g.config.authorizer == nil {
+		return nil
+	}
+	if err := g.config.authorizer(r, access, name); err != nil {
+		return fmt.Errorf("access '%s' to topic '%s' is denied: %w", access, name, err)
+	}
+	return nil
+}
+
+func (g *Gateway) servePublish(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	publish, err := g.getPublish(r, r.URL.Query().Get("topic"))
+	if err != nil {
+		http.Error(w, err.Error(), httpStatusOf(err))
+		return
+	}
+	var payload json.RawMessage
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, g.config.maxPublishSize)).Decode(&payload); err != nil {
+		http.Error(w, fmt.Sprintf("unable to read the event: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := publish(r.Context(), payload); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func httpStatusOf(err error) int {
+	if errors.Is(err, ErrTopicNotAllowed) {
+		return http.StatusNotFound
+	}
+	return http.StatusForbidden
+}
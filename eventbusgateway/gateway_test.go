@@ -0,0 +1,116 @@
+package eventbusgateway
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/stretchr/testify/require"
+	"github.com/xaionaro-go/eventbus"
+)
+
+type testEvent struct {
+	Value int
+}
+
+func newTestGateway(t *testing.T) (*eventbus.EventBus, *httptest.Server) {
+	bus := eventbus.New()
+	gw := New(bus, OptionAuthorizer(func(r *http.Request, access Access, topicName string) error {
+		if r.URL.Query().Get("token") != "secret" {
+			return errors.New("invalid token")
+		}
+		return nil
+	}))
+	AllowSubscribe[testEvent, testEvent](gw, "test", testEvent{})
+	AllowPublish[testEvent, testEvent](gw, "test", testEvent{})
+	srv := httptest.NewServer(gw)
+	t.Cleanup(srv.Close)
+	return bus, srv
+}
+
+func waitSubscriptions(t *testing.T, bus *eventbus.EventBus, count int) {
+	require.Eventually(t, func() bool {
+		return len(bus.AbstractSubscriptions(testEvent{})) == count
+	}, time.Second, time.Millisecond)
+}
+
+func TestGatewayWebSocket(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	bus, srv := newTestGateway(t)
+
+	conn, _, err := websocket.Dial(ctx, srv.URL+"/ws?token=secret", nil)
+	require.NoError(t, err)
+	defer conn.CloseNow()
+
+	require.NoError(t, wsjson.Write(ctx, conn, Message{Type: MessageTypeSubscribe, Topic: "unknown"}))
+	var msg Message
+	require.NoError(t, wsjson.Read(ctx, conn, &msg))
+	require.Equal(t, MessageTypeError, msg.Type)
+
+	require.NoError(t, wsjson.Write(ctx, conn, Message{Type: MessageTypeSubscribe, Topic: "test"}))
+	waitSubscriptions(t, bus, 1)
+	eventbus.SendEvent(ctx, bus, testEvent{Value: 1})
+	msg = Message{}
+	require.NoError(t, wsjson.Read(ctx, conn, &msg))
+	require.Equal(t, Message{Type: MessageTypeEvent, Topic: "test", Event: []byte(`{"Value":1}`)}, msg)
+
+	sub := eventbus.Subscribe[testEvent](ctx, bus)
+	defer sub.Finish(ctx)
+	require.NoError(t, wsjson.Write(ctx, conn, Message{Type: MessageTypePublish, Topic: "test", Event: []byte(`{"Value":2}`)}))
+	require.Equal(t, testEvent{Value: 2}, <-sub.EventChan())
+	require.NoError(t, wsjson.Read(ctx, conn, &msg))
+	require.Equal(t, []byte(`{"Value":2}`), []byte(msg.Event))
+
+	require.NoError(t, wsjson.Write(ctx, conn, Message{Type: MessageTypeUnsubscribe, Topic: "test"}))
+	waitSubscriptions(t, bus, 1)
+
+	// the subscriptions are finished when the client disconnects
+	require.NoError(t, wsjson.Write(ctx, conn, Message{Type: MessageTypeSubscribe, Topic: "test"}))
+	waitSubscriptions(t, bus, 2)
+	conn.CloseNow()
+	waitSubscriptions(t, bus, 1)
+}
+
+func TestGatewaySSE(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	bus, srv := newTestGateway(t)
+
+	resp, err := http.Get(srv.URL + "/sse?topic=test")
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/sse?topic=test&token=secret", nil)
+	require.NoError(t, err)
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	waitSubscriptions(t, bus, 1)
+
+	resp2, err := http.Post(srv.URL+"/publish?topic=test&token=secret", "application/json", strings.NewReader(`{"Value":1}`))
+	require.NoError(t, err)
+	resp2.Body.Close()
+	require.Equal(t, http.StatusNoContent, resp2.StatusCode)
+
+	r := bufio.NewReader(resp.Body)
+	line, err := r.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "event: test\n", line)
+	line, err = r.ReadString('\n')
+	require.NoError(t, err)
+	require.Equal(t, "data: {\"Value\":1}\n", line)
+
+	// the subscription is finished when the client disconnects
+	resp.Body.Close()
+	waitSubscriptions(t, bus, 0)
+}
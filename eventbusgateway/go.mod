@@ -0,0 +1,24 @@
+module github.com/xaionaro-go/eventbus/eventbusgateway
+
+go 1.24.1
+
+require (
+	github.com/coder/websocket v1.8.14
+	github.com/facebookincubator/go-belt v0.0.0-20250308011339-62fb7027b11f
+	github.com/stretchr/testify v1.10.0
+	github.com/xaionaro-go/eventbus v0.0.0
+	github.com/xaionaro-go/xcontext v0.0.0-20250111150717-e70e1f5b299c
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-ng/slices v0.0.0-20230703171042-6195d35636a2 // indirect
+	github.com/go-ng/sort v0.0.0-20220617173827-2cc7cd04f7c7 // indirect
+	github.com/go-ng/xsort v0.0.0-20220617174223-1d146907bccc // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/exp v0.0.0-20230519143937-03e91628a987 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/xaionaro-go/eventbus => ../
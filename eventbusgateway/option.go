@@ -0,0 +1,85 @@
+package eventbusgateway
+
+import (
+	"github.com/coder/websocket"
+	"github.com/xaionaro-go/eventbus"
+)
+
+type Option interface {
+	apply(*config)
+}
+
+type config struct {
+	onOverflow     eventbus.OnOverflow
+	queueSize      uint
+	authorizer     Authorizer
+	acceptOptions  *websocket.AcceptOptions
+	maxPublishSize int64
+}
+
+type Options []Option
+
+func (s Options) Config() config {
+	cfg := config{
+		onOverflow:     eventbus.OnOverflowDrop{},
+		queueSize:      64,
+		maxPublishSize: 1 << 20,
+	}
+	for _, opt := range s {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+type optionOnOverflowT struct {
+	eventbus.OnOverflow
+}
+
+// OptionOnOverflow defines what to do with the events if a client
+// cannot keep up. With eventbus.OnOverflowClose (and similar) the slow
+// client is disconnected.
+//
+// The default is eventbus.OnOverflowDrop.
+func OptionOnOverflow(v eventbus.OnOverflow) optionOnOverflowT {
+	return optionOnOverflowT{
+		OnOverflow: v,
+	}
+}
+
+func (opt optionOnOverflowT) apply(cfg *config) {
+	cfg.onOverflow = opt.OnOverflow
+}
+
+// OptionQueueSize sets the size of the queue of events
+// (per topic per client). The default is 64.
+type OptionQueueSize uint
+
+func (opt OptionQueueSize) apply(cfg *config) {
+	cfg.queueSize = uint(opt)
+}
+
+// OptionAuthorizer sets the Authorizer of the requests. By default
+// any client may access any allowed topic.
+type OptionAuthorizer Authorizer
+
+func (opt OptionAuthorizer) apply(cfg *config) {
+	cfg.authorizer = Authorizer(opt)
+}
+
+// OptionAcceptOptions sets the options of accepting WebSocket connections
+// (e.g. the allowed origins).
+type OptionAcceptOptions struct {
+	*websocket.AcceptOptions
+}
+
+func (opt OptionAcceptOptions) apply(cfg *config) {
+	cfg.acceptOptions = opt.AcceptOptions
+}
+
+// OptionMaxPublishSize sets the maximal size of a published event
+// in bytes. The default is 1MiB.
+type OptionMaxPublishSize int64
+
+func (opt OptionMaxPublishSize) apply(cfg *config) {
+	cfg.maxPublishSize = int64(opt)
+}
@@ -0,0 +1,78 @@
+package eventbusgateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+type sseConn struct {
+	locker   sync.Mutex
+	w        http.ResponseWriter
+	flusher  http.Flusher
+	cancelFn context.CancelFunc
+}
+
+var _ sink = (*sseConn)(nil)
+
+func (c *sseConn) sendEvent(ctx context.Context, topicName string, event json.RawMessage) error {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	// JSON produced by json.Marshal is always a single line
+	if _, err := fmt.Fprintf(c.w, "event: %s\ndata: %s\n\n", topicName, event); err != nil {
+		return err
+	}
+	c.flusher.Flush()
+	return nil
+}
+
+func (c *sseConn) abort(err error) {
+	c.locker.Lock()
+	defer c.locker.Unlock()
+	fmt.Fprintf(c.w, "event: error\ndata: %s\n\n", strings.ReplaceAll(err.Error(), "\n", " "))
+	c.flusher.Flush()
+	c.cancelFn()
+}
+
+func (g *Gateway) serveSSE(w http.ResponseWriter, r *http.Request) {
+	names := r.URL.Query()["topic"]
+	if len(names) == 0 {
+		http.Error(w, "no topics requested", http.StatusBadRequest)
+		return
+	}
+	subscribes := make([]subscribeFunc, 0, len(names))
+	for _, name := range names {
+		subscribe, err := g.getSubscribe(r, name)
+		if err != nil {
+			http.Error(w, err.Error(), httpStatusOf(err))
+			return
+		}
+		subscribes = append(subscribes, subscribe)
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	ctx, cancelFn := context.WithCancel(r.Context())
+	defer cancelFn()
+	c := &sseConn{
+		w:        w,
+		flusher:  flusher,
+		cancelFn: cancelFn,
+	}
+	for _, subscribe := range subscribes {
+		defer subscribe(ctx, c)()
+	}
+	c.locker.Lock()
+	flusher.Flush()
+	c.locker.Unlock()
+	<-ctx.Done()
+}
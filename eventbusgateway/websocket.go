@@ -0,0 +1,138 @@
+package eventbusgateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/coder/websocket"
+	"github.com/coder/websocket/wsjson"
+	"github.com/facebookincubator/go-belt/tool/logger"
+)
+
+// MessageType is the type of a Message.
+type MessageType string
+
+const (
+	// MessageTypeSubscribe is sent by a client to subscribe to Message.Topic.
+	MessageTypeSubscribe = MessageType("subscribe")
+
+	// MessageTypeUnsubscribe is sent by a client to unsubscribe from Message.Topic.
+	MessageTypeUnsubscribe = MessageType("unsubscribe")
+
+	// MessageTypePublish is sent by a client to publish Message.Event to Message.Topic.
+	MessageTypePublish = MessageType("publish")
+
+	// MessageTypeEvent is sent to a client on each event of a subscribed topic.
+	MessageTypeEvent = MessageType("event")
+
+	// MessageTypeError is sent to a client if its request has failed.
+	MessageTypeError = MessageType("error")
+)
+
+// Message is a message of the WebSocket protocol of the Gateway
+// (each WebSocket message is a Message in JSON).
+type Message struct {
+	Type  MessageType     `json:"type"`
+	Topic string          `json:"topic,omitempty"`
+	Event json.RawMessage `json:"event,omitempty"`
+	Error string          `json:"error,omitempty"`
+}
+
+type wsConn struct {
+	conn *websocket.Conn
+}
+
+var _ sink = (*wsConn)(nil)
+
+func (c *wsConn) sendEvent(ctx context.Context, topicName string, event json.RawMessage) error {
+	return wsjson.Write(ctx, c.conn, Message{
+		Type:  MessageTypeEvent,
+		Topic: topicName,
+		Event: event,
+	})
+}
+
+func (c *wsConn) sendError(ctx context.Context, topicName string, err error) error {
+	return wsjson.Write(ctx, c.conn, Message{
+		Type:  MessageTypeError,
+		Topic: topicName,
+		Error: err.Error(),
+	})
+}
+
+func (c *wsConn) abort(err error) {
+	// not waiting for the closing handshake, since it
+	// is handled by the reading loop
+	go c.conn.Close(websocket.StatusPolicyViolation, err.Error())
+}
+
+func (g *Gateway) serveWebSocket(w http.ResponseWriter, r *http.Request) {
+	conn, err := websocket.Accept(w, r, g.config.acceptOptions)
+	if err != nil {
+		// the error response is already written by Accept
+		return
+	}
+	defer conn.CloseNow()
+	conn.SetReadLimit(g.config.maxPublishSize)
+	ctx := r.Context()
+	c := &wsConn{conn: conn}
+
+	subscriptions := map[string]func(){}
+	defer func() {
+		for _, finish := range subscriptions {
+			finish()
+		}
+	}()
+	for {
+		var msg Message
+		if err := wsjson.Read(ctx, conn, &msg); err != nil {
+			logger.Debugf(ctx, "the WebSocket connection is closed: %v", err)
+			return
+		}
+		if err := g.handleMessage(ctx, r, c, subscriptions, msg); err != nil {
+			if err := c.sendError(ctx, msg.Topic, err); err != nil {
+				logger.Debugf(ctx, "unable to send an error: %v", err)
+				return
+			}
+		}
+	}
+}
+
+func (g *Gateway) handleMessage(
+	ctx context.Context,
+	r *http.Request,
+	c *wsConn,
+	subscriptions map[string]func(),
+	msg Message,
+) error {
+	switch msg.Type {
+	case MessageTypeSubscribe:
+		if _, ok := subscriptions[msg.Topic]; ok {
+			return nil
+		}
+		subscribe, err := g.getSubscribe(r, msg.Topic)
+		if err != nil {
+			return err
+		}
+		subscriptions[msg.Topic] = subscribe(ctx, c)
+		return nil
+	case MessageTypeUnsubscribe:
+		finish, ok := subscriptions[msg.Topic]
+		if !ok {
+			return fmt.Errorf("not subscribed to topic '%s'", msg.Topic)
+		}
+		delete(subscriptions, msg.Topic)
+		finish()
+		return nil
+	case MessageTypePublish:
+		publish, err := g.getPublish(r, msg.Topic)
+		if err != nil {
+			return err
+		}
+		return publish(ctx, msg.Event)
+	default:
+		return fmt.Errorf("unexpected message type '%s'", msg.Type)
+	}
+}
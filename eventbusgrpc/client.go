@@ -0,0 +1,231 @@
+package eventbusgrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/xaionaro-go/eventbus"
+	"github.com/xaionaro-go/eventbus/eventbuscodec"
+	"google.golang.org/grpc"
+)
+
+var (
+	ErrTopicNotRegistered = errors.New("the topic is not registered in the codec registry")
+)
+
+// Client is a remote EventBus (see Server).
+type Client struct {
+	client   EventBusClient
+	registry *eventbuscodec.Registry
+}
+
+// NewClient returns a new Client using the connection.
+func NewClient(
+	conn grpc.ClientConnInterface,
+	registry *eventbuscodec.Registry,
+) *Client {
+	return &Client{
+		client:   NewEventBusClient(conn),
+		registry: registry,
+	}
+}
+
+func (c *Client) encode(topic, event any) (*Event, error) {
+	if _, ok := c.registry.TopicName(topic); !ok {
+		return nil, fmt.Errorf("%w: %#+v", ErrTopicNotRegistered, topic)
+	}
+	env, err := c.registry.Encode(topic, event)
+	if err != nil {
+		return nil, err
+	}
+	return eventFromEnvelope(env), nil
+}
+
+func decode[E any](registry *eventbuscodec.Registry, ev *Event) (E, error) {
+	var zeroValue E
+	_, _event, err := registry.Decode(envelopeFromEvent(ev))
+	if err != nil {
+		return zeroValue, err
+	}
+	event, ok := _event.(E)
+	if !ok {
+		return zeroValue, fmt.Errorf("invalid type %T, expected %T", _event, zeroValue)
+	}
+	return event, nil
+}
+
+// SendEvent is the same as eventbus.SendEvent, but for a remote EventBus.
+func SendEvent[E any](
+	ctx context.Context,
+	c *Client,
+	event E,
+) (eventbus.SendEventResult, error) {
+	var zeroValue E
+	return SendEventWithCustomTopic(ctx, c, zeroValue, event)
+}
+
+// SendEventWithCustomTopic is the same as eventbus.SendEventWithCustomTopic,
+// but for a remote EventBus.
+func SendEventWithCustomTopic[T, E any](
+	ctx context.Context,
+	c *Client,
+	topic T,
+	event E,
+) (eventbus.SendEventResult, error) {
+	ev, err := c.encode(topic, event)
+	if err != nil {
+		return eventbus.SendEventResult{}, err
+	}
+	resp, err := c.client.Publish(ctx, &PublishRequest{Event: ev})
+	if err != nil {
+		return eventbus.SendEventResult{}, err
+	}
+	return eventbus.SendEventResult{
+		SentCountImmediate: uint(resp.GetSentCountImmediate()),
+		SentCountDeferred:  uint(resp.GetSentCountDeferred()),
+		PiledCount:         uint(resp.GetPiledCount()),
+		DropCountImmediate: uint(resp.GetDropCountImmediate()),
+		DropCountDeferred:  uint(resp.GetDropCountDeferred()),
+	}, nil
+}
+
+// Request sends the event to the topic of a remote EventBus and returns
+// the first event received on the reply topic (after the request is sent).
+//
+// The replies are not correlated with requests, thus concurrent
+// requests with the same reply topic may receive each other's replies:
+// the reply topic must be unique per call (or per caller making one call
+// at a time).
+func Request[R, E any](
+	ctx context.Context,
+	c *Client,
+	topic any,
+	event E,
+	replyTopic any,
+) (R, error) {
+	var zeroValue R
+	ev, err := c.encode(topic, event)
+	if err != nil {
+		return zeroValue, err
+	}
+	replyTopicName, ok := c.registry.TopicName(replyTopic)
+	if !ok {
+		return zeroValue, fmt.Errorf("%w: %#+v", ErrTopicNotRegistered, replyTopic)
+	}
+	resp, err := c.client.Request(ctx, &RequestRequest{
+		Event:      ev,
+		ReplyTopic: replyTopicName,
+	})
+	if err != nil {
+		return zeroValue, err
+	}
+	return decode[R](c.registry, resp.GetReply())
+}
+
+// Subscription is a subscription to a topic of a remote EventBus.
+type Subscription[T, E any] struct {
+	topic     T
+	eventChan chan E
+	cancelFn  context.CancelFunc
+	errLocker sync.Mutex
+	err       error
+}
+
+// Subscribe is the same as eventbus.Subscribe, but for a remote EventBus.
+func Subscribe[E any](
+	ctx context.Context,
+	c *Client,
+) (*Subscription[E, E], error) {
+	var zeroValue E
+	return SubscribeWithCustomTopic[E, E](ctx, c, zeroValue)
+}
+
+// SubscribeWithCustomTopic is the same as eventbus.SubscribeWithCustomTopic,
+// but for a remote EventBus. It returns after the subscription is established.
+func SubscribeWithCustomTopic[T, E any](
+	ctx context.Context,
+	c *Client,
+	topic T,
+) (*Subscription[T, E], error) {
+	name, ok := c.registry.TopicName(topic)
+	if !ok {
+		return nil, fmt.Errorf("%w: %#+v", ErrTopicNotRegistered, topic)
+	}
+	ctx, cancelFn := context.WithCancel(ctx)
+	stream, err := c.client.Subscribe(ctx, &SubscribeRequest{Topic: name})
+	if err != nil {
+		cancelFn()
+		return nil, err
+	}
+	md, err := stream.Header()
+	if err == nil && md == nil {
+		// the stream is terminated without headers, getting the status
+		_, err = stream.Recv()
+	}
+	if err != nil {
+		cancelFn()
+		return nil, err
+	}
+
+	sub := &Subscription[T, E]{
+		topic:     topic,
+		eventChan: make(chan E),
+		cancelFn:  cancelFn,
+	}
+	go func() {
+		defer close(sub.eventChan)
+		for {
+			ev, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF && ctx.Err() == nil {
+					sub.setErr(err)
+				}
+				return
+			}
+			event, err := decode[E](c.registry, ev)
+			if err != nil {
+				sub.setErr(err)
+				cancelFn()
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case sub.eventChan <- event:
+			}
+		}
+	}()
+	return sub, nil
+}
+
+func (sub *Subscription[T, E]) setErr(err error) {
+	sub.errLocker.Lock()
+	defer sub.errLocker.Unlock()
+	sub.err = err
+}
+
+// Topic returns the topic of the subscription.
+func (sub *Subscription[T, E]) Topic() T {
+	return sub.topic
+}
+
+// EventChan returns the channel of the events. It is closed when
+// the subscription is finished or has failed (see Err).
+func (sub *Subscription[T, E]) EventChan() <-chan E {
+	return sub.eventChan
+}
+
+// Err returns the reason the subscription has failed (if it has).
+func (sub *Subscription[T, E]) Err() error {
+	sub.errLocker.Lock()
+	defer sub.errLocker.Unlock()
+	return sub.err
+}
+
+// Finish unsubscribes from the topic.
+func (sub *Subscription[T, E]) Finish() {
+	sub.cancelFn()
+}
@@ -0,0 +1,20 @@
+// Package eventbusgrpc exposes an EventBus as a gRPC service (see eventbus.proto),
+// so that remote (possibly non-Go) processes could publish and subscribe.
+//
+// The events are serialized with the codec registry, so each exposed
+// topic should be registered there (under the same name in all processes).
+//
+// Usage:
+//
+//	// server:
+//	grpcServer := grpc.NewServer()
+//	eventbusgrpc.RegisterEventBusServer(grpcServer, eventbusgrpc.NewServer(bus, reg))
+//
+//	// client:
+//	client := eventbusgrpc.NewClient(conn, reg)
+//	sub, err := eventbusgrpc.Subscribe[MyEvent](ctx, client)
+//	...
+//	_, err = eventbusgrpc.SendEvent(ctx, client, MyEvent{})
+package eventbusgrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative eventbus.proto
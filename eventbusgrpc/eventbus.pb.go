@@ -0,0 +1,447 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: eventbus.proto
+
+package eventbusgrpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Event is a serialized event of a named topic
+// (see eventbuscodec.Envelope).
+type Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Topic         string                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`
+	Codec         string                 `protobuf:"bytes,3,opt,name=codec,proto3" json:"codec,omitempty"`
+	Payload       []byte                 `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_eventbus_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbus_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_eventbus_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Event) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetCodec() string {
+	if x != nil {
+		return x.Codec
+	}
+	return ""
+}
+
+func (x *Event) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+type PublishRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PublishRequest) Reset() {
+	*x = PublishRequest{}
+	mi := &file_eventbus_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishRequest) ProtoMessage() {}
+
+func (x *PublishRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbus_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishRequest.ProtoReflect.Descriptor instead.
+func (*PublishRequest) Descriptor() ([]byte, []int) {
+	return file_eventbus_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PublishRequest) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+// PublishResponse mirrors eventbus.SendEventResult.
+type PublishResponse struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	SentCountImmediate uint64                 `protobuf:"varint,1,opt,name=sent_count_immediate,json=sentCountImmediate,proto3" json:"sent_count_immediate,omitempty"`
+	SentCountDeferred  uint64                 `protobuf:"varint,2,opt,name=sent_count_deferred,json=sentCountDeferred,proto3" json:"sent_count_deferred,omitempty"`
+	PiledCount         uint64                 `protobuf:"varint,3,opt,name=piled_count,json=piledCount,proto3" json:"piled_count,omitempty"`
+	DropCountImmediate uint64                 `protobuf:"varint,4,opt,name=drop_count_immediate,json=dropCountImmediate,proto3" json:"drop_count_immediate,omitempty"`
+	DropCountDeferred  uint64                 `protobuf:"varint,5,opt,name=drop_count_deferred,json=dropCountDeferred,proto3" json:"drop_count_deferred,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *PublishResponse) Reset() {
+	*x = PublishResponse{}
+	mi := &file_eventbus_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishResponse) ProtoMessage() {}
+
+func (x *PublishResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbus_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishResponse.ProtoReflect.Descriptor instead.
+func (*PublishResponse) Descriptor() ([]byte, []int) {
+	return file_eventbus_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PublishResponse) GetSentCountImmediate() uint64 {
+	if x != nil {
+		return x.SentCountImmediate
+	}
+	return 0
+}
+
+func (x *PublishResponse) GetSentCountDeferred() uint64 {
+	if x != nil {
+		return x.SentCountDeferred
+	}
+	return 0
+}
+
+func (x *PublishResponse) GetPiledCount() uint64 {
+	if x != nil {
+		return x.PiledCount
+	}
+	return 0
+}
+
+func (x *PublishResponse) GetDropCountImmediate() uint64 {
+	if x != nil {
+		return x.DropCountImmediate
+	}
+	return 0
+}
+
+func (x *PublishResponse) GetDropCountDeferred() uint64 {
+	if x != nil {
+		return x.DropCountDeferred
+	}
+	return 0
+}
+
+type SubscribeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Topic         string                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeRequest) Reset() {
+	*x = SubscribeRequest{}
+	mi := &file_eventbus_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeRequest) ProtoMessage() {}
+
+func (x *SubscribeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbus_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return file_eventbus_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SubscribeRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+type RequestRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Event         *Event                 `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	ReplyTopic    string                 `protobuf:"bytes,2,opt,name=reply_topic,json=replyTopic,proto3" json:"reply_topic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestRequest) Reset() {
+	*x = RequestRequest{}
+	mi := &file_eventbus_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestRequest) ProtoMessage() {}
+
+func (x *RequestRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbus_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestRequest.ProtoReflect.Descriptor instead.
+func (*RequestRequest) Descriptor() ([]byte, []int) {
+	return file_eventbus_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *RequestRequest) GetEvent() *Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *RequestRequest) GetReplyTopic() string {
+	if x != nil {
+		return x.ReplyTopic
+	}
+	return ""
+}
+
+type RequestResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reply         *Event                 `protobuf:"bytes,1,opt,name=reply,proto3" json:"reply,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequestResponse) Reset() {
+	*x = RequestResponse{}
+	mi := &file_eventbus_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequestResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestResponse) ProtoMessage() {}
+
+func (x *RequestResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_eventbus_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestResponse.ProtoReflect.Descriptor instead.
+func (*RequestResponse) Descriptor() ([]byte, []int) {
+	return file_eventbus_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RequestResponse) GetReply() *Event {
+	if x != nil {
+		return x.Reply
+	}
+	return nil
+}
+
+var File_eventbus_proto protoreflect.FileDescriptor
+
+const file_eventbus_proto_rawDesc = "" +
+	"\n" +
+	"\x0eeventbus.proto\x12\veventbus.v1\"a\n" +
+	"\x05Event\x12\x14\n" +
+	"\x05topic\x18\x01 \x01(\tR\x05topic\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x14\n" +
+	"\x05codec\x18\x03 \x01(\tR\x05codec\x12\x18\n" +
+	"\apayload\x18\x04 \x01(\fR\apayload\":\n" +
+	"\x0ePublishRequest\x12(\n" +
+	"\x05event\x18\x01 \x01(\v2\x12.eventbus.v1.EventR\x05event\"\xf6\x01\n" +
+	"\x0fPublishResponse\x120\n" +
+	"\x14sent_count_immediate\x18\x01 \x01(\x04R\x12sentCountImmediate\x12.\n" +
+	"\x13sent_count_deferred\x18\x02 \x01(\x04R\x11sentCountDeferred\x12\x1f\n" +
+	"\vpiled_count\x18\x03 \x01(\x04R\n" +
+	"piledCount\x120\n" +
+	"\x14drop_count_immediate\x18\x04 \x01(\x04R\x12dropCountImmediate\x12.\n" +
+	"\x13drop_count_deferred\x18\x05 \x01(\x04R\x11dropCountDeferred\"(\n" +
+	"\x10SubscribeRequest\x12\x14\n" +
+	"\x05topic\x18\x01 \x01(\tR\x05topic\"[\n" +
+	"\x0eRequestRequest\x12(\n" +
+	"\x05event\x18\x01 \x01(\v2\x12.eventbus.v1.EventR\x05event\x12\x1f\n" +
+	"\vreply_topic\x18\x02 \x01(\tR\n" +
+	"replyTopic\";\n" +
+	"\x0fRequestResponse\x12(\n" +
+	"\x05reply\x18\x01 \x01(\v2\x12.eventbus.v1.EventR\x05reply2\xd8\x01\n" +
+	"\bEventBus\x12D\n" +
+	"\aPublish\x12\x1b.eventbus.v1.PublishRequest\x1a\x1c.eventbus.v1.PublishResponse\x12@\n" +
+	"\tSubscribe\x12\x1d.eventbus.v1.SubscribeRequest\x1a\x12.eventbus.v1.Event0\x01\x12D\n" +
+	"\aRequest\x12\x1b.eventbus.v1.RequestRequest\x1a\x1c.eventbus.v1.RequestResponseB.Z,github.com/xaionaro-go/eventbus/eventbusgrpcb\x06proto3"
+
+var (
+	file_eventbus_proto_rawDescOnce sync.Once
+	file_eventbus_proto_rawDescData []byte
+)
+
+func file_eventbus_proto_rawDescGZIP() []byte {
+	file_eventbus_proto_rawDescOnce.Do(func() {
+		file_eventbus_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_eventbus_proto_rawDesc), len(file_eventbus_proto_rawDesc)))
+	})
+	return file_eventbus_proto_rawDescData
+}
+
+var file_eventbus_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_eventbus_proto_goTypes = []any{
+	(*Event)(nil),            // 0: eventbus.v1.Event
+	(*PublishRequest)(nil),   // 1: eventbus.v1.PublishRequest
+	(*PublishResponse)(nil),  // 2: eventbus.v1.PublishResponse
+	(*SubscribeRequest)(nil), // 3: eventbus.v1.SubscribeRequest
+	(*RequestRequest)(nil),   // 4: eventbus.v1.RequestRequest
+	(*RequestResponse)(nil),  // 5: eventbus.v1.RequestResponse
+}
+var file_eventbus_proto_depIdxs = []int32{
+	0, // 0: eventbus.v1.PublishRequest.event:type_name -> eventbus.v1.Event
+	0, // 1: eventbus.v1.RequestRequest.event:type_name -> eventbus.v1.Event
+	0, // 2: eventbus.v1.RequestResponse.reply:type_name -> eventbus.v1.Event
+	1, // 3: eventbus.v1.EventBus.Publish:input_type -> eventbus.v1.PublishRequest
+	3, // 4: eventbus.v1.EventBus.Subscribe:input_type -> eventbus.v1.SubscribeRequest
+	4, // 5: eventbus.v1.EventBus.Request:input_type -> eventbus.v1.RequestRequest
+	2, // 6: eventbus.v1.EventBus.Publish:output_type -> eventbus.v1.PublishResponse
+	0, // 7: eventbus.v1.EventBus.Subscribe:output_type -> eventbus.v1.Event
+	5, // 8: eventbus.v1.EventBus.Request:output_type -> eventbus.v1.RequestResponse
+	6, // [6:9] is the sub-list for method output_type
+	3, // [3:6] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_eventbus_proto_init() }
+func file_eventbus_proto_init() {
+	if File_eventbus_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_eventbus_proto_rawDesc), len(file_eventbus_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_eventbus_proto_goTypes,
+		DependencyIndexes: file_eventbus_proto_depIdxs,
+		MessageInfos:      file_eventbus_proto_msgTypes,
+	}.Build()
+	File_eventbus_proto = out.File
+	file_eventbus_proto_goTypes = nil
+	file_eventbus_proto_depIdxs = nil
+}
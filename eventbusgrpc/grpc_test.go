@@ -0,0 +1,109 @@
+package eventbusgrpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xaionaro-go/eventbus"
+	"github.com/xaionaro-go/eventbus/eventbuscodec"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testEvent struct {
+	Value int
+}
+
+type testReply struct {
+	Value int
+}
+
+func newTestRegistry(t *testing.T) *eventbuscodec.Registry {
+	reg := eventbuscodec.NewRegistry()
+	require.NoError(t, eventbuscodec.RegisterEventType[testEvent](reg, "test-event", eventbuscodec.JSON{}))
+	require.NoError(t, eventbuscodec.RegisterEventType[testReply](reg, "test-reply", eventbuscodec.JSON{}))
+	return reg
+}
+
+func newTestClient(t *testing.T, bus *eventbus.EventBus) *Client {
+	listener := bufconn.Listen(1 << 20)
+	grpcServer := grpc.NewServer()
+	RegisterEventBusServer(grpcServer, NewServer(bus, newTestRegistry(t)))
+	go grpcServer.Serve(listener)
+	t.Cleanup(grpcServer.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return NewClient(conn, newTestRegistry(t))
+}
+
+func TestGRPC(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	bus := eventbus.New()
+	client := newTestClient(t, bus)
+
+	// subscribe
+	sub, err := Subscribe[testEvent](ctx, client)
+	require.NoError(t, err)
+	r := eventbus.SendEvent(ctx, bus, testEvent{Value: 1})
+	require.Equal(t, uint(1), r.SentCountImmediate)
+	require.Equal(t, testEvent{Value: 1}, <-sub.EventChan())
+	sub.Finish()
+	for range sub.EventChan() {
+	}
+	require.NoError(t, sub.Err())
+	require.Eventually(t, func() bool {
+		return len(bus.AbstractSubscriptions(testEvent{})) == 0
+	}, time.Second, time.Millisecond)
+
+	// publish
+	localSub := eventbus.Subscribe[testEvent](ctx, bus)
+	defer localSub.Finish(ctx)
+	r, err = SendEvent(ctx, client, testEvent{Value: 2})
+	require.NoError(t, err)
+	require.Equal(t, uint(1), r.SentCountImmediate)
+	require.Equal(t, testEvent{Value: 2}, <-localSub.EventChan())
+
+	// request
+	go func() {
+		ev := <-localSub.EventChan()
+		eventbus.SendEvent(ctx, bus, testReply{Value: ev.Value * 10})
+	}()
+	reply, err := Request[testReply](ctx, client, testEvent{}, testEvent{Value: 3}, testReply{})
+	require.NoError(t, err)
+	require.Equal(t, testReply{Value: 30}, reply)
+
+	// a topic unknown to the server
+	err = eventbuscodec.RegisterEventType[int](client.registry, "int", eventbuscodec.JSON{})
+	require.NoError(t, err)
+	_, err = Subscribe[int](ctx, client)
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCBusClosed(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	bus := eventbus.New()
+	client := newTestClient(t, bus)
+
+	sub, err := Subscribe[testEvent](ctx, client)
+	require.NoError(t, err)
+	require.NoError(t, bus.Close(ctx))
+	for range sub.EventChan() {
+	}
+	require.Equal(t, codes.Unavailable, status.Code(sub.Err()))
+}
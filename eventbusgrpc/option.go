@@ -0,0 +1,54 @@
+package eventbusgrpc
+
+import (
+	"github.com/xaionaro-go/eventbus"
+)
+
+type Option interface {
+	apply(*config)
+}
+
+type config struct {
+	onOverflow eventbus.OnOverflow
+	queueSize  uint
+}
+
+type Options []Option
+
+func (s Options) Config() config {
+	cfg := config{
+		onOverflow: eventbus.OnOverflowDrop{},
+		queueSize:  1024,
+	}
+	for _, opt := range s {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+type optionOnOverflowT struct {
+	eventbus.OnOverflow
+}
+
+// OptionOnOverflow defines what to do with the events if a subscribed
+// client cannot keep up. With eventbus.OnOverflowClose (and similar)
+// the stream of the slow client is terminated.
+//
+// The default is eventbus.OnOverflowDrop.
+func OptionOnOverflow(v eventbus.OnOverflow) optionOnOverflowT {
+	return optionOnOverflowT{
+		OnOverflow: v,
+	}
+}
+
+func (opt optionOnOverflowT) apply(cfg *config) {
+	cfg.onOverflow = opt.OnOverflow
+}
+
+// OptionQueueSize sets the size of the queue of events
+// (per subscribed client). The default is 1024.
+type OptionQueueSize uint
+
+func (opt OptionQueueSize) apply(cfg *config) {
+	cfg.queueSize = uint(opt)
+}
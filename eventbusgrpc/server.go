@@ -0,0 +1,170 @@
+package eventbusgrpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/xaionaro-go/eventbus"
+	"github.com/xaionaro-go/eventbus/eventbuscodec"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements EventBusServer on top of an EventBus.
+//
+// All the topics registered in the codec registry are accessible.
+type Server struct {
+	UnimplementedEventBusServer
+
+	bus      *eventbus.EventBus
+	registry *eventbuscodec.Registry
+	config   config
+}
+
+var _ EventBusServer = (*Server)(nil)
+
+// NewServer returns a new Server of the EventBus.
+func NewServer(
+	bus *eventbus.EventBus,
+	registry *eventbuscodec.Registry,
+	opts ...Option,
+) *Server {
+	return &Server{
+		bus:      bus,
+		registry: registry,
+		config:   Options(opts).Config(),
+	}
+}
+
+// Publish implements EventBusServer.
+func (s *Server) Publish(
+	ctx context.Context,
+	req *PublishRequest,
+) (*PublishResponse, error) {
+	if req.GetEvent() == nil {
+		return nil, status.Error(codes.InvalidArgument, "no event")
+	}
+	result, err := s.registry.Send(ctx, s.bus, envelopeFromEvent(req.GetEvent()))
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	return &PublishResponse{
+		SentCountImmediate: uint64(result.SentCountImmediate),
+		SentCountDeferred:  uint64(result.SentCountDeferred),
+		PiledCount:         uint64(result.PiledCount),
+		DropCountImmediate: uint64(result.DropCountImmediate),
+		DropCountDeferred:  uint64(result.DropCountDeferred),
+	}, nil
+}
+
+// Subscribe implements EventBusServer.
+func (s *Server) Subscribe(
+	req *SubscribeRequest,
+	stream EventBus_SubscribeServer,
+) error {
+	ctx := stream.Context()
+	sub, err := s.registry.Subscribe(
+		ctx, s.bus, req.GetTopic(),
+		eventbus.OptionOnOverflow(s.config.onOverflow),
+		eventbus.OptionQueueSize(s.config.queueSize),
+	)
+	if err != nil {
+		return statusOf(err)
+	}
+	defer sub.Finish()
+
+	// letting the client know the subscription is established
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case env, ok := <-sub.EventChan():
+			if !ok {
+				return closedStatusOf(sub.Err())
+			}
+			if err := stream.Send(eventFromEnvelope(env)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Request implements EventBusServer.
+func (s *Server) Request(
+	ctx context.Context,
+	req *RequestRequest,
+) (*RequestResponse, error) {
+	if req.GetEvent() == nil {
+		return nil, status.Error(codes.InvalidArgument, "no event")
+	}
+	sub, err := s.registry.Subscribe(
+		ctx, s.bus, req.GetReplyTopic(),
+		eventbus.OptionOnOverflow(eventbus.OnOverflowDrop{}),
+		eventbus.OptionQueueSize(1),
+	)
+	if err != nil {
+		return nil, statusOf(err)
+	}
+	defer sub.Finish()
+
+	if _, err := s.registry.Send(ctx, s.bus, envelopeFromEvent(req.GetEvent())); err != nil {
+		return nil, statusOf(err)
+	}
+	select {
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	case env, ok := <-sub.EventChan():
+		if !ok {
+			return nil, closedStatusOf(sub.Err())
+		}
+		return &RequestResponse{
+			Reply: eventFromEnvelope(env),
+		}, nil
+	}
+}
+
+func statusOf(err error) error {
+	switch {
+	case errors.Is(err, eventbuscodec.ErrNotRegistered):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	default:
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+}
+
+// closedStatusOf returns the status to end a stream with when
+// the subscription was closed for the given reason.
+func closedStatusOf(err error) error {
+	switch {
+	case errors.Is(err, eventbus.ErrSubscriptionOverflow):
+		return status.Error(codes.ResourceExhausted, "the subscription is closed, the client is too slow")
+	case errors.Is(err, eventbus.ErrBusClosed):
+		return status.Error(codes.Unavailable, err.Error())
+	default:
+		return status.Error(codes.Canceled, "the subscription is finished")
+	}
+}
+
+func eventFromEnvelope(env eventbuscodec.Envelope) *Event {
+	return &Event{
+		Topic:   env.Topic,
+		Type:    env.Type,
+		Codec:   env.Codec,
+		Payload: env.Payload,
+	}
+}
+
+func envelopeFromEvent(ev *Event) eventbuscodec.Envelope {
+	return eventbuscodec.Envelope{
+		Topic:   ev.GetTopic(),
+		Type:    ev.GetType(),
+		Codec:   ev.GetCodec(),
+		Payload: ev.GetPayload(),
+	}
+}
@@ -0,0 +1,149 @@
+// Package eventbusotel provides an opt-in OpenTelemetry tracing integration
+// for eventbus.
+//
+// Usage:
+//
+//	tracing := eventbusotel.New()
+//	bus := eventbus.New(tracing.BusOptions()...)
+//	sub := eventbus.SubscribeWithContext[MyEvent](ctx, bus)
+//	for ev := range sub.EventChan() {
+//		ctx, span := tracing.StartConsumerSpan(ctx, ev.Context, "handle MyEvent")
+//		// ...
+//		span.End()
+//	}
+package eventbusotel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xaionaro-go/eventbus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/xaionaro-go/eventbus"
+	messagingSystem     = "eventbus"
+)
+
+// Tracing is a SendHook and a ContextPropagator of an EventBus that
+// starts a span per sent event and carries its span context to
+// the subscribers.
+type Tracing struct {
+	tracer trace.Tracer
+}
+
+var _ eventbus.SendHook = (*Tracing)(nil)
+var _ eventbus.ContextPropagator = (*Tracing)(nil)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+}
+
+type Option interface {
+	apply(*config)
+}
+
+type OptionTracerProvider struct {
+	trace.TracerProvider
+}
+
+func (opt OptionTracerProvider) apply(cfg *config) {
+	cfg.tracerProvider = opt.TracerProvider
+}
+
+func New(opts ...Option) *Tracing {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+	}
+	for _, opt := range opts {
+		opt.apply(&cfg)
+	}
+	return &Tracing{
+		tracer: cfg.tracerProvider.Tracer(instrumentationName),
+	}
+}
+
+// BusOptions returns the options to be passed to eventbus.New
+// to enable the tracing.
+func (t *Tracing) BusOptions() eventbus.BusOptions {
+	return eventbus.BusOptions{
+		eventbus.BusOptionSendHook{SendHook: t},
+		eventbus.BusOptionContextPropagator{ContextPropagator: t},
+	}
+}
+
+// BeforeSend implements eventbus.SendHook.
+func (t *Tracing) BeforeSend(
+	ctx context.Context,
+	topic, event any,
+) context.Context {
+	ctx, _ = t.tracer.Start(
+		ctx, "eventbus send",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", messagingSystem),
+			attribute.String("messaging.destination.name", fmt.Sprintf("%#+v", topic)),
+			attribute.String("messaging.eventbus.event_type", fmt.Sprintf("%T", event)),
+		),
+	)
+	return ctx
+}
+
+// AfterSend implements eventbus.SendHook.
+func (t *Tracing) AfterSend(
+	ctx context.Context,
+	topic, event any,
+	result eventbus.SendEventResult,
+) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+	span.SetAttributes(
+		attribute.Int64("messaging.eventbus.sent_immediate", int64(result.SentCountImmediate)),
+		attribute.Int64("messaging.eventbus.sent_deferred", int64(result.SentCountDeferred)),
+		attribute.Int64("messaging.eventbus.piled", int64(result.PiledCount)),
+		attribute.Int64("messaging.eventbus.dropped_immediate", int64(result.DropCountImmediate)),
+		attribute.Int64("messaging.eventbus.dropped_deferred", int64(result.DropCountDeferred)),
+	)
+	if result.DropCountImmediate > 0 || result.DropCountDeferred > 0 {
+		span.SetStatus(codes.Error, "some subscribers did not receive the event")
+	}
+}
+
+// PropagateContext implements eventbus.ContextPropagator.
+func (t *Tracing) PropagateContext(
+	publisherCtx context.Context,
+	deliveryCtx context.Context,
+) context.Context {
+	spanCtx := trace.SpanContextFromContext(publisherCtx)
+	if !spanCtx.IsValid() {
+		return deliveryCtx
+	}
+	return trace.ContextWithRemoteSpanContext(deliveryCtx, spanCtx)
+}
+
+// StartConsumerSpan starts a span of processing an event received
+// with the given delivery context (see eventbus.EventWithContext).
+//
+// The span is linked to the span of the publisher, and if ctx has no span
+// then the publisher's span is also used as the parent, so that the trace
+// is not broken by the bus hop.
+func (t *Tracing) StartConsumerSpan(
+	ctx context.Context,
+	deliveryCtx context.Context,
+	spanName string,
+	opts ...trace.SpanStartOption,
+) (context.Context, trace.Span) {
+	publisherSpanCtx := trace.SpanContextFromContext(deliveryCtx)
+	if publisherSpanCtx.IsValid() {
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			ctx = trace.ContextWithRemoteSpanContext(ctx, publisherSpanCtx)
+		}
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: publisherSpanCtx}))
+	}
+	opts = append(opts, trace.WithSpanKind(trace.SpanKindConsumer))
+	return t.tracer.Start(ctx, spanName, opts...)
+}
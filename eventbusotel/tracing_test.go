@@ -0,0 +1,42 @@
+package eventbusotel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xaionaro-go/eventbus"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing(t *testing.T) {
+	ctx := context.Background()
+	recorder := tracetest.NewSpanRecorder()
+	tracing := New(OptionTracerProvider{
+		TracerProvider: sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)),
+	})
+	bus := eventbus.New(tracing.BusOptions()...)
+
+	sub := eventbus.SubscribeWithContext[int](ctx, bus, eventbus.OptionQueueSize(1))
+	defer sub.Finish(ctx)
+
+	r := eventbus.SendEvent(ctx, bus, 1)
+	require.Equal(t, eventbus.SendEventResult{SentCountImmediate: 1}, r)
+
+	ev := <-sub.EventChan()
+	require.Equal(t, 1, ev.Event)
+	_, span := tracing.StartConsumerSpan(ctx, ev.Context, "consume")
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	producer, consumer := spans[0], spans[1]
+	require.Equal(t, trace.SpanKindProducer, producer.SpanKind())
+	require.Equal(t, trace.SpanKindConsumer, consumer.SpanKind())
+	require.Equal(t, producer.SpanContext().TraceID(), consumer.SpanContext().TraceID())
+	require.Equal(t, producer.SpanContext().SpanID(), consumer.Parent().SpanID())
+	require.Len(t, consumer.Links(), 1)
+	require.Equal(t, producer.SpanContext().SpanID(), consumer.Links()[0].SpanContext.SpanID())
+}
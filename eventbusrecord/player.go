@@ -0,0 +1,142 @@
+package eventbusrecord
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/xaionaro-go/eventbus"
+)
+
+// ErrUnknownTopic is returned by Player.Play if the log contains a topic
+// that was not added to the Player.
+var ErrUnknownTopic = errors.New("unknown topic")
+
+type topicPlayer func(ctx context.Context, bus *eventbus.EventBus, rawEvent json.RawMessage) error
+
+// Player re-publishes the events recorded by a Recorder.
+type Player struct {
+	bus    *eventbus.EventBus
+	topics map[string]topicPlayer
+}
+
+func NewPlayer(bus *eventbus.EventBus) *Player {
+	return &Player{
+		bus:    bus,
+		topics: map[string]topicPlayer{},
+	}
+}
+
+// PlayTopic makes the Player to publish the recorded events of the topic
+// of the given name (see Recorder.RecordTopic) as events of type E to the given topic.
+func PlayTopic[T, E any](
+	p *Player,
+	name string,
+	topic T,
+) {
+	p.topics[name] = func(ctx context.Context, bus *eventbus.EventBus, rawEvent json.RawMessage) error {
+		var ev E
+		if err := json.Unmarshal(rawEvent, &ev); err != nil {
+			return fmt.Errorf("unable to deserialize %T: %w", ev, err)
+		}
+		eventbus.SendEventWithCustomTopic(ctx, bus, topic, ev)
+		return nil
+	}
+}
+
+// PlayEventType is the same as PlayTopic, but for topics used by
+// eventbus.SendEvent and eventbus.Subscribe.
+func PlayEventType[E any](
+	p *Player,
+	name string,
+) {
+	var zeroValue E
+	PlayTopic[E, E](p, name, zeroValue)
+}
+
+// PlayOption is an option of Player.Play.
+type PlayOption interface {
+	applyToPlay(*playConfig)
+}
+
+type playConfig struct {
+	originalTiming bool
+}
+
+type PlayOptions []PlayOption
+
+func (s PlayOptions) Config() playConfig {
+	cfg := playConfig{}
+	for _, opt := range s {
+		opt.applyToPlay(&cfg)
+	}
+	return cfg
+}
+
+// PlayOptionOriginalTiming makes the Player to reproduce the original
+// time intervals between the events (instead of sending them as fast as possible).
+type PlayOptionOriginalTiming bool
+
+func (opt PlayOptionOriginalTiming) applyToPlay(cfg *playConfig) {
+	cfg.originalTiming = bool(opt)
+}
+
+// Play reads the records from the given reader and publishes them to the EventBus.
+func (p *Player) Play(
+	ctx context.Context,
+	r io.Reader,
+	opts ...PlayOption,
+) error {
+	cfg := PlayOptions(opts).Config()
+	decoder := json.NewDecoder(r)
+	var (
+		startedAt      time.Time
+		firstEventTime time.Time
+	)
+	for idx := 0; ; idx++ {
+		var record Record
+		if err := decoder.Decode(&record); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("unable to read record #%d: %w", idx, err)
+		}
+		play, ok := p.topics[record.Topic]
+		if !ok {
+			return fmt.Errorf("record #%d: %w: '%s'", idx, ErrUnknownTopic, record.Topic)
+		}
+		if cfg.originalTiming {
+			if idx == 0 {
+				startedAt = time.Now()
+				firstEventTime = record.Time
+			}
+			if err := sleepUntil(ctx, startedAt.Add(record.Time.Sub(firstEventTime))); err != nil {
+				return err
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := play(ctx, p.bus, record.Event); err != nil {
+			return fmt.Errorf("record #%d: %w", idx, err)
+		}
+	}
+}
+
+func sleepUntil(ctx context.Context, t time.Time) error {
+	d := time.Until(t)
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
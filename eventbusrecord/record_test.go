@@ -0,0 +1,52 @@
+package eventbusrecord
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xaionaro-go/eventbus"
+)
+
+type testEvent struct {
+	Value int
+}
+
+func TestRecordAndPlay(t *testing.T) {
+	ctx := context.Background()
+
+	var log bytes.Buffer
+	rec := NewRecorder(&log)
+	RecordEventType[testEvent](rec, "test-event")
+	rec.RecordTopic("strings", "strings")
+	bus := eventbus.New(eventbus.BusOptionSendHook{SendHook: rec})
+
+	eventbus.SendEvent(ctx, bus, testEvent{Value: 1})
+	eventbus.SendEventWithCustomTopic(ctx, bus, "strings", "hello")
+	eventbus.SendEventWithCustomTopic(ctx, bus, "not-recorded", "hello")
+	time.Sleep(10 * time.Millisecond)
+	eventbus.SendEvent(ctx, bus, testEvent{Value: 2})
+	require.NoError(t, rec.Err())
+
+	bus = eventbus.New()
+	player := NewPlayer(bus)
+	PlayEventType[testEvent](player, "test-event")
+	PlayTopic[string, string](player, "strings", "strings")
+	subEvents := eventbus.Subscribe[testEvent](ctx, bus, eventbus.OptionQueueSize(10))
+	defer subEvents.Finish(ctx)
+	subStrings := eventbus.SubscribeWithCustomTopic[string, string](ctx, bus, "strings", eventbus.OptionQueueSize(10))
+	defer subStrings.Finish(ctx)
+
+	startedAt := time.Now()
+	require.NoError(t, player.Play(ctx, bytes.NewReader(log.Bytes()), PlayOptionOriginalTiming(true)))
+	require.GreaterOrEqual(t, time.Since(startedAt), 10*time.Millisecond)
+	require.Equal(t, testEvent{Value: 1}, <-subEvents.EventChan())
+	require.Equal(t, testEvent{Value: 2}, <-subEvents.EventChan())
+	require.Equal(t, "hello", <-subStrings.EventChan())
+	require.Len(t, subStrings.EventChan(), 0)
+
+	err := NewPlayer(bus).Play(ctx, bytes.NewReader(log.Bytes()))
+	require.ErrorIs(t, err, ErrUnknownTopic)
+}
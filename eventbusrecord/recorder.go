@@ -0,0 +1,128 @@
+// Package eventbusrecord provides a recorder of events sent to an EventBus,
+// and a player to re-publish the recorded events.
+package eventbusrecord
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/facebookincubator/go-belt/tool/logger"
+	"github.com/xaionaro-go/eventbus"
+)
+
+// Record is a single recorded event (one line in the log).
+type Record struct {
+	Time  time.Time       `json:"time"`
+	Topic string          `json:"topic"`
+	Type  string          `json:"type"`
+	Event json.RawMessage `json:"event"`
+}
+
+// Recorder is an eventbus.SendHook that writes the events sent
+// to the selected topics to an io.Writer (as JSON lines of Record).
+//
+// Since it is a SendHook, the events are recorded exactly in the order
+// they are sent (even across different topics).
+//
+// Usage:
+//
+//	rec := eventbusrecord.NewRecorder(w)
+//	rec.RecordTopic("my-topic", "my-topic")
+//	eventbusrecord.RecordEventType[MyEvent](rec, "my-event")
+//	bus := eventbus.New(eventbus.BusOptionSendHook{SendHook: rec})
+type Recorder struct {
+	locker  sync.Mutex
+	encoder *json.Encoder
+	topics  map[any]string
+	err     error
+}
+
+var _ eventbus.SendHook = (*Recorder)(nil)
+
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{
+		encoder: json.NewEncoder(w),
+		topics:  map[any]string{},
+	}
+}
+
+// RecordTopic starts recording the given topic under the given name
+// (the same name should be used in Player).
+func (r *Recorder) RecordTopic(name string, topic any) {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	r.topics[topic] = name
+}
+
+// RecordEventType is the same as RecordTopic, but for topics used by
+// eventbus.SendEvent and eventbus.Subscribe.
+func RecordEventType[E any](r *Recorder, name string) {
+	var zeroValue E
+	r.RecordTopic(name, zeroValue)
+}
+
+// StopRecordingTopic stops recording the given topic.
+func (r *Recorder) StopRecordingTopic(topic any) {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	delete(r.topics, topic)
+}
+
+// BeforeSend implements eventbus.SendHook.
+func (r *Recorder) BeforeSend(
+	ctx context.Context,
+	topic, event any,
+) context.Context {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	name, ok := r.topics[topic]
+	if !ok {
+		return ctx
+	}
+	if err := r.record(name, event); err != nil {
+		logger.Errorf(ctx, "unable to record an event of topic '%s': %v", name, err)
+		if r.err == nil {
+			r.err = err
+		}
+	}
+	return ctx
+}
+
+// AfterSend implements eventbus.SendHook.
+func (r *Recorder) AfterSend(
+	ctx context.Context,
+	topic, event any,
+	result eventbus.SendEventResult,
+) {
+}
+
+func (r *Recorder) record(
+	name string,
+	event any,
+) error {
+	b, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("unable to serialize %T: %w", event, err)
+	}
+	err = r.encoder.Encode(Record{
+		Time:  time.Now(),
+		Topic: name,
+		Type:  fmt.Sprintf("%T", event),
+		Event: b,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to write the record: %w", err)
+	}
+	return nil
+}
+
+// Err returns the first error occurred during recording.
+func (r *Recorder) Err() error {
+	r.locker.Lock()
+	defer r.locker.Unlock()
+	return r.err
+}
@@ -0,0 +1,166 @@
+// Package eventbussnapshot saves the retained events of an EventBus
+// (see eventbus.BusOptionRetainLastEvents) and the offsets of the durable
+// subscriptions of a WAL (see eventbuswal.SubscribeDurable), and restores
+// them on startup, so that a restarted process resumes with the same
+// last known values of each topic without replaying the full logs.
+//
+// Usage:
+//
+//	bus := eventbus.New(eventbus.BusOptionRetainLastEvents(true))
+//	...
+//	err := eventbussnapshot.Save(ctx, w, bus, reg, wal)
+//	...
+//	// after a restart:
+//	err := eventbussnapshot.Load(ctx, r, bus, reg, wal)
+package eventbussnapshot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/facebookincubator/go-belt/tool/logger"
+	"github.com/xaionaro-go/eventbus"
+	"github.com/xaionaro-go/eventbus/eventbuscodec"
+	"github.com/xaionaro-go/eventbus/eventbuswal"
+)
+
+// FormatVersion is the version of the snapshot format written by Save.
+const FormatVersion = 1
+
+var ErrUnsupportedVersion = errors.New("unsupported snapshot format version")
+
+// Snapshot is the saved state of an EventBus.
+type Snapshot struct {
+	Version int       `json:"version"`
+	Time    time.Time `json:"time"`
+
+	// Retained are the retained events of the topics
+	// registered in the codec registry.
+	Retained []eventbuscodec.Envelope `json:"retained,omitempty"`
+
+	// ConsumerOffsets are the stored offsets of the durable subscriptions
+	// by topic name and then by subscription name.
+	ConsumerOffsets map[string]map[string]uint64 `json:"consumer_offsets,omitempty"`
+}
+
+// Take collects the Snapshot of the bus state.
+//
+// The retained events of topics not registered in the codec registry
+// are skipped. The WAL is optional (may be nil).
+func Take(
+	ctx context.Context,
+	bus *eventbus.EventBus,
+	registry *eventbuscodec.Registry,
+	wal *eventbuswal.WAL,
+) (Snapshot, error) {
+	s := Snapshot{
+		Version: FormatVersion,
+		Time:    time.Now(),
+	}
+	retained := bus.RetainedEvents()
+	for topic, event := range retained {
+		if _, ok := registry.TopicName(topic); !ok {
+			logger.Debugf(ctx, "skipping the retained event of an unregistered topic %#+v", topic)
+			continue
+		}
+		env, err := registry.Encode(topic, event)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("unable to encode the retained event of topic %#+v: %w", topic, err)
+		}
+		s.Retained = append(s.Retained, env)
+	}
+	if wal == nil {
+		return s, nil
+	}
+	for _, topic := range wal.Topics() {
+		name, ok := registry.TopicName(topic)
+		if !ok {
+			return Snapshot{}, fmt.Errorf("%w: %#+v", eventbuswal.ErrTopicNotRegistered, topic)
+		}
+		offsets, err := wal.ConsumerOffsets(topic)
+		if err != nil {
+			return Snapshot{}, fmt.Errorf("unable to get the consumer offsets of topic '%s': %w", name, err)
+		}
+		if len(offsets) == 0 {
+			continue
+		}
+		if s.ConsumerOffsets == nil {
+			s.ConsumerOffsets = map[string]map[string]uint64{}
+		}
+		s.ConsumerOffsets[name] = offsets
+	}
+	return s, nil
+}
+
+// Restore applies the Snapshot to the bus state: sets the retained
+// events (without sending them) and the consumer offsets of the WAL
+// (if not nil).
+func (s Snapshot) Restore(
+	ctx context.Context,
+	bus *eventbus.EventBus,
+	registry *eventbuscodec.Registry,
+	wal *eventbuswal.WAL,
+) error {
+	if s.Version != FormatVersion {
+		return fmt.Errorf("%w: %d", ErrUnsupportedVersion, s.Version)
+	}
+	for _, env := range s.Retained {
+		topic, event, err := registry.Decode(env)
+		if err != nil {
+			return fmt.Errorf("unable to decode the retained event of topic '%s': %w", env.Topic, err)
+		}
+		bus.SetRetainedEvent(topic, event)
+	}
+	if wal == nil {
+		return nil
+	}
+	for name, offsets := range s.ConsumerOffsets {
+		topic, ok := registry.Topic(name)
+		if !ok {
+			return fmt.Errorf("topic '%s': %w", name, eventbuscodec.ErrNotRegistered)
+		}
+		for consumer, offset := range offsets {
+			if err := wal.SetConsumerOffset(topic, consumer, offset); err != nil {
+				return fmt.Errorf("unable to restore the offset of '%s' of topic '%s': %w", consumer, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Save takes a Snapshot and writes it to the io.Writer as JSON.
+func Save(
+	ctx context.Context,
+	w io.Writer,
+	bus *eventbus.EventBus,
+	registry *eventbuscodec.Registry,
+	wal *eventbuswal.WAL,
+) error {
+	s, err := Take(ctx, bus, registry, wal)
+	if err != nil {
+		return err
+	}
+	if err := json.NewEncoder(w).Encode(s); err != nil {
+		return fmt.Errorf("unable to write the snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load reads a Snapshot written by Save and restores it.
+func Load(
+	ctx context.Context,
+	r io.Reader,
+	bus *eventbus.EventBus,
+	registry *eventbuscodec.Registry,
+	wal *eventbuswal.WAL,
+) error {
+	var s Snapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return fmt.Errorf("unable to read the snapshot: %w", err)
+	}
+	return s.Restore(ctx, bus, registry, wal)
+}
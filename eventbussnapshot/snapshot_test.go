@@ -0,0 +1,64 @@
+package eventbussnapshot
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xaionaro-go/eventbus"
+	"github.com/xaionaro-go/eventbus/eventbuscodec"
+	"github.com/xaionaro-go/eventbus/eventbuswal"
+)
+
+type testEvent struct {
+	Value int
+}
+
+func TestSnapshot(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	dir := t.TempDir()
+
+	newProcess := func() (*eventbus.EventBus, *eventbuscodec.Registry, *eventbuswal.WAL) {
+		reg := eventbuscodec.NewRegistry()
+		require.NoError(t, eventbuscodec.RegisterEventType[testEvent](reg, "test-event", eventbuscodec.JSON{}))
+		wal, err := eventbuswal.Open(dir, reg)
+		require.NoError(t, err)
+		require.NoError(t, wal.AddTopic(testEvent{}))
+		opts := append(wal.BusOptions(), eventbus.BusOptionRetainLastEvents(true))
+		return eventbus.New(opts...), reg, wal
+	}
+
+	bus, reg, wal := newProcess()
+	for i := range 3 {
+		eventbus.SendEvent(ctx, bus, testEvent{Value: i})
+	}
+	eventbus.SendEventWithCustomTopic(ctx, bus, "unregistered", testEvent{Value: -1})
+	require.NoError(t, wal.SetConsumerOffset(testEvent{}, "consumer", 2))
+
+	var buf bytes.Buffer
+	require.NoError(t, Save(ctx, &buf, bus, reg, wal))
+	require.NoError(t, wal.SetConsumerOffset(testEvent{}, "consumer", 0))
+	require.NoError(t, wal.Close())
+
+	// restart
+	bus, reg, wal = newProcess()
+	defer wal.Close()
+	require.NoError(t, Load(ctx, &buf, bus, reg, wal))
+
+	retained, ok := bus.RetainedEvent(testEvent{})
+	require.True(t, ok)
+	require.Equal(t, testEvent{Value: 2}, retained)
+	_, ok = bus.RetainedEvent("unregistered")
+	require.False(t, ok)
+
+	offsets, err := wal.ConsumerOffsets(testEvent{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{"consumer": 2}, offsets)
+
+	sub := eventbus.Subscribe[testEvent](ctx, bus, eventbus.OptionReceiveRetained(true))
+	defer sub.Finish(ctx)
+	require.Equal(t, testEvent{Value: 2}, <-sub.EventChan())
+}
@@ -0,0 +1,190 @@
+package eventbussourcing
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/xaionaro-go/eventbus/eventbuscodec"
+)
+
+const aggregateFileExt = ".events"
+
+// FileStore is a Store keeping the events of each aggregate in a separate
+// file in a directory. The events are serialized with the given Codec.
+//
+// The store is safe for concurrent use within a process, but not across processes.
+type FileStore[E any] struct {
+	locker   sync.Mutex
+	dir      string
+	codec    eventbuscodec.Codec
+	versions map[string]uint64
+}
+
+var _ Store[struct{}] = (*FileStore[struct{}])(nil)
+
+func NewFileStore[E any](
+	dir string,
+	codec eventbuscodec.Codec,
+) (*FileStore[E], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create directory '%s': %w", dir, err)
+	}
+	return &FileStore[E]{
+		dir:      dir,
+		codec:    codec,
+		versions: map[string]uint64{},
+	}, nil
+}
+
+func (s *FileStore[E]) path(aggregateID string) string {
+	return filepath.Join(s.dir, url.PathEscape(aggregateID)+aggregateFileExt)
+}
+
+func (s *FileStore[E]) Load(
+	ctx context.Context,
+	aggregateID string,
+) ([]Event[E], error) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	return s.loadAndRepair(aggregateID)
+}
+
+// loadAndRepair loads the events of the aggregate, drops a partially
+// written record at the end of the file (if any) and caches the version
+// of the aggregate, so that the next Append writes right after
+// the last valid record.
+func (s *FileStore[E]) loadAndRepair(aggregateID string) ([]Event[E], error) {
+	events, validSize, err := s.load(aggregateID)
+	if err != nil {
+		return nil, err
+	}
+	path := s.path(aggregateID)
+	if err := os.Truncate(path, validSize); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("unable to truncate '%s' to %d: %w", path, validSize, err)
+	}
+	s.versions[aggregateID] = uint64(len(events))
+	return events, nil
+}
+
+// load returns the events of the aggregate and the size of the file
+// they occupy: a partially written record at the end of the file
+// (e.g. after a crash) is ignored.
+func (s *FileStore[E]) load(aggregateID string) ([]Event[E], int64, error) {
+	path := s.path(aggregateID)
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, 0, nil
+		}
+		return nil, 0, fmt.Errorf("unable to open '%s': %w", path, err)
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to stat '%s': %w", path, err)
+	}
+
+	var (
+		events    []Event[E]
+		validSize int64
+	)
+	r := bufio.NewReader(f)
+	for {
+		var header [4]byte
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return events, validSize, nil
+			}
+			return nil, 0, fmt.Errorf("unable to read '%s': %w", path, err)
+		}
+		size := binary.BigEndian.Uint32(header[:])
+		if int64(size) > stat.Size()-validSize-int64(len(header)) {
+			// a partially written record (or a corrupted size, which is not
+			// to be trusted before allocating the buffer)
+			return events, validSize, nil
+		}
+		b := make([]byte, size)
+		if _, err := io.ReadFull(r, b); err != nil {
+			if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+				return events, validSize, nil
+			}
+			return nil, 0, fmt.Errorf("unable to read '%s': %w", path, err)
+		}
+		var ev Event[E]
+		if err := s.codec.Unmarshal(b, &ev); err != nil {
+			return nil, 0, fmt.Errorf("unable to deserialize event #%d of '%s': %w", len(events), aggregateID, err)
+		}
+		events = append(events, ev)
+		validSize += int64(len(header) + len(b))
+	}
+}
+
+func (s *FileStore[E]) Append(
+	ctx context.Context,
+	aggregateID string,
+	expectedVersion uint64,
+	events []E,
+) ([]Event[E], error) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	currentVersion, ok := s.versions[aggregateID]
+	if !ok {
+		stored, err := s.loadAndRepair(aggregateID)
+		if err != nil {
+			return nil, err
+		}
+		currentVersion = uint64(len(stored))
+	}
+	if currentVersion != expectedVersion {
+		return nil, fmt.Errorf("%w: aggregate '%s' is at version %d, expected %d", ErrConcurrencyConflict, aggregateID, currentVersion, expectedVersion)
+	}
+
+	appended := newEvents(aggregateID, expectedVersion, time.Now(), events)
+	var buf []byte
+	for _, ev := range appended {
+		b, err := s.codec.Marshal(&ev)
+		if err != nil {
+			return nil, fmt.Errorf("unable to serialize event of version %d: %w", ev.Version, err)
+		}
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(b)))
+		buf = append(buf, b...)
+	}
+
+	path := s.path(aggregateID)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open '%s': %w", path, err)
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("unable to stat '%s': %w", path, err)
+	}
+	_, err = f.Write(buf)
+	if err == nil {
+		err = f.Sync()
+	}
+	if err != nil {
+		// do not leave a partially written record
+		_ = f.Truncate(stat.Size())
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// re-read (and truncate if needed) the file on the next Append
+		delete(s.versions, aggregateID)
+		return nil, fmt.Errorf("unable to write '%s': %w", path, err)
+	}
+	s.versions[aggregateID] = currentVersion + uint64(len(appended))
+	return appended, nil
+}
@@ -0,0 +1,112 @@
+package eventbussourcing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xaionaro-go/eventbus"
+)
+
+// Reducer folds an event into the state of an aggregate.
+type Reducer[S, E any] func(state S, ev Event[E]) S
+
+// Aggregate is an event-sourced aggregate loaded by a Repository.
+type Aggregate[S, E any] struct {
+	ID      string
+	State   S
+	Version uint64
+
+	reducer Reducer[S, E]
+	pending []E
+}
+
+// Raise adds an event to be appended on Repository.Commit, and applies
+// it to the state right away.
+func (a *Aggregate[S, E]) Raise(ev E) {
+	a.pending = append(a.pending, ev)
+	a.State = a.reducer(a.State, Event[E]{
+		AggregateID: a.ID,
+		Version:     a.Version + uint64(len(a.pending)),
+		Data:        ev,
+	})
+}
+
+// Pending returns the raised but not committed events.
+func (a *Aggregate[S, E]) Pending() []E {
+	return a.pending
+}
+
+// Repository loads and commits aggregates of state S built from events E.
+//
+// The committed events are sent to the EventBus as Event[E]
+// (thus subscribe to them with eventbus.Subscribe[eventbussourcing.Event[E]]).
+type Repository[S, E any] struct {
+	store   Store[E]
+	bus     *eventbus.EventBus
+	reducer Reducer[S, E]
+	initial S
+}
+
+// NewRepository returns a new Repository. The bus may be nil if
+// publishing is not needed.
+func NewRepository[S, E any](
+	store Store[E],
+	bus *eventbus.EventBus,
+	initial S,
+	reducer Reducer[S, E],
+) *Repository[S, E] {
+	return &Repository[S, E]{
+		store:   store,
+		bus:     bus,
+		reducer: reducer,
+		initial: initial,
+	}
+}
+
+// Load rebuilds the aggregate from its events.
+func (r *Repository[S, E]) Load(
+	ctx context.Context,
+	aggregateID string,
+) (*Aggregate[S, E], error) {
+	events, err := r.store.Load(ctx, aggregateID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the events of '%s': %w", aggregateID, err)
+	}
+	a := &Aggregate[S, E]{
+		ID:      aggregateID,
+		State:   r.initial,
+		reducer: r.reducer,
+	}
+	for _, ev := range events {
+		a.State = r.reducer(a.State, ev)
+		a.Version = ev.Version
+	}
+	return a, nil
+}
+
+// Commit appends the pending events of the aggregate to the store,
+// and publishes them to the EventBus.
+//
+// Returns an error wrapping ErrConcurrencyConflict if the aggregate
+// was committed by someone else since it was loaded; in this
+// case the aggregate should be reloaded.
+func (r *Repository[S, E]) Commit(
+	ctx context.Context,
+	a *Aggregate[S, E],
+) error {
+	if len(a.pending) == 0 {
+		return nil
+	}
+	appended, err := r.store.Append(ctx, a.ID, a.Version, a.pending)
+	if err != nil {
+		return fmt.Errorf("unable to append the events of '%s': %w", a.ID, err)
+	}
+	a.pending = nil
+	a.Version = appended[len(appended)-1].Version
+	if r.bus != nil {
+		for _, ev := range appended {
+			eventbus.SendEvent(ctx, r.bus, ev)
+		}
+	}
+	return nil
+}
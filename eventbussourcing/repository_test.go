@@ -0,0 +1,126 @@
+package eventbussourcing
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xaionaro-go/eventbus"
+	"github.com/xaionaro-go/eventbus/eventbuscodec"
+)
+
+type deposited struct {
+	Amount int
+}
+
+type account struct {
+	Balance int
+}
+
+func reduceAccount(state account, ev Event[deposited]) account {
+	state.Balance += ev.Data.Amount
+	return state
+}
+
+func TestRepository(t *testing.T) {
+	fileStore, err := NewFileStore[deposited](t.TempDir(), eventbuscodec.Gob{})
+	require.NoError(t, err)
+	for name, store := range map[string]Store[deposited]{
+		"memory": NewMemoryStore[deposited](),
+		"file":   fileStore,
+	} {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			bus := eventbus.New()
+			sub := eventbus.Subscribe[Event[deposited]](ctx, bus, eventbus.OptionQueueSize(10))
+			defer sub.Finish(ctx)
+			repo := NewRepository(store, bus, account{}, reduceAccount)
+
+			a, err := repo.Load(ctx, "acc/1")
+			require.NoError(t, err)
+			a.Raise(deposited{Amount: 10})
+			a.Raise(deposited{Amount: 5})
+			require.Equal(t, 15, a.State.Balance)
+			require.NoError(t, repo.Commit(ctx, a))
+			require.Equal(t, uint64(2), a.Version)
+
+			ev := <-sub.EventChan()
+			require.Equal(t, "acc/1", ev.AggregateID)
+			require.Equal(t, uint64(1), ev.Version)
+			require.Equal(t, deposited{Amount: 10}, ev.Data)
+			require.Equal(t, uint64(2), (<-sub.EventChan()).Version)
+
+			a1, err := repo.Load(ctx, "acc/1")
+			require.NoError(t, err)
+			require.Equal(t, 15, a1.State.Balance)
+			require.Equal(t, uint64(2), a1.Version)
+			a2, err := repo.Load(ctx, "acc/1")
+			require.NoError(t, err)
+
+			a1.Raise(deposited{Amount: 1})
+			require.NoError(t, repo.Commit(ctx, a1))
+			a2.Raise(deposited{Amount: 2})
+			require.ErrorIs(t, repo.Commit(ctx, a2), ErrConcurrencyConflict)
+			require.Len(t, sub.EventChan(), 1)
+
+			a, err = repo.Load(ctx, "acc/1")
+			require.NoError(t, err)
+			require.Equal(t, 16, a.State.Balance)
+			require.Equal(t, uint64(3), a.Version)
+		})
+	}
+}
+
+func TestFileStorePartialRecord(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := NewFileStore[deposited](dir, eventbuscodec.Gob{})
+	require.NoError(t, err)
+	_, err = store.Append(ctx, "acc", 0, []deposited{{Amount: 1}})
+	require.NoError(t, err)
+
+	// simulate a crash in the middle of writing a record
+	writeTail := func(b []byte) {
+		f, err := os.OpenFile(store.path("acc"), os.O_WRONLY|os.O_APPEND, 0o644)
+		require.NoError(t, err)
+		_, err = f.Write(b)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	}
+	writeTail([]byte{0, 0, 1, 0, 42})
+
+	store, err = NewFileStore[deposited](dir, eventbuscodec.Gob{})
+	require.NoError(t, err)
+	events, err := store.Load(ctx, "acc")
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+
+	// appending right after loading
+	_, err = store.Append(ctx, "acc", 1, []deposited{{Amount: 2}})
+	require.NoError(t, err)
+	store, err = NewFileStore[deposited](dir, eventbuscodec.Gob{})
+	require.NoError(t, err)
+	events, err = store.Load(ctx, "acc")
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	require.Equal(t, deposited{Amount: 2}, events[1].Data)
+
+	// appending without loading
+	writeTail([]byte{0, 0, 1})
+	store, err = NewFileStore[deposited](dir, eventbuscodec.Gob{})
+	require.NoError(t, err)
+	_, err = store.Append(ctx, "acc", 2, []deposited{{Amount: 3}})
+	require.NoError(t, err)
+	events, err = store.Load(ctx, "acc")
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+
+	// a corrupted size is not trusted
+	writeTail([]byte{0xff, 0xff, 0xff, 0xff, 42})
+	store, err = NewFileStore[deposited](dir, eventbuscodec.Gob{})
+	require.NoError(t, err)
+	events, err = store.Load(ctx, "acc")
+	require.NoError(t, err)
+	require.Len(t, events, 3)
+}
@@ -0,0 +1,98 @@
+// Package eventbussourcing provides event-sourced aggregates built on top
+// of an EventBus: the events of an aggregate are appended to a Store,
+// the state is rebuilt by folding the events through a Reducer, and
+// the appended events are published to the EventBus on commit.
+package eventbussourcing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrConcurrencyConflict is returned if the aggregate was modified
+// since it was loaded (optimistic concurrency control).
+var ErrConcurrencyConflict = errors.New("concurrency conflict")
+
+// Event is a stored event of an aggregate.
+type Event[E any] struct {
+	AggregateID string
+	// Version is the version of the aggregate after this event (starting from 1).
+	Version uint64
+	Time    time.Time
+	Data    E
+}
+
+// Store is a storage of events of aggregates.
+type Store[E any] interface {
+	// Load returns all the events of the aggregate.
+	Load(ctx context.Context, aggregateID string) ([]Event[E], error)
+
+	// Append appends the events to the aggregate if its current version
+	// is expectedVersion, otherwise returns ErrConcurrencyConflict.
+	Append(ctx context.Context, aggregateID string, expectedVersion uint64, events []E) ([]Event[E], error)
+}
+
+// MemoryStore is an in-memory Store.
+type MemoryStore[E any] struct {
+	locker     sync.Mutex
+	aggregates map[string][]Event[E]
+}
+
+var _ Store[struct{}] = (*MemoryStore[struct{}])(nil)
+
+func NewMemoryStore[E any]() *MemoryStore[E] {
+	return &MemoryStore[E]{
+		aggregates: map[string][]Event[E]{},
+	}
+}
+
+func (s *MemoryStore[E]) Load(
+	ctx context.Context,
+	aggregateID string,
+) ([]Event[E], error) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	events := s.aggregates[aggregateID]
+	result := make([]Event[E], len(events))
+	copy(result, events)
+	return result, nil
+}
+
+func (s *MemoryStore[E]) Append(
+	ctx context.Context,
+	aggregateID string,
+	expectedVersion uint64,
+	events []E,
+) ([]Event[E], error) {
+	s.locker.Lock()
+	defer s.locker.Unlock()
+	stored := s.aggregates[aggregateID]
+	if currentVersion := uint64(len(stored)); currentVersion != expectedVersion {
+		return nil, fmt.Errorf("%w: aggregate '%s' is at version %d, expected %d", ErrConcurrencyConflict, aggregateID, currentVersion, expectedVersion)
+	}
+	appended := newEvents(aggregateID, expectedVersion, time.Now(), events)
+	s.aggregates[aggregateID] = append(stored, appended...)
+	return appended, nil
+}
+
+func newEvents[E any](
+	aggregateID string,
+	version uint64,
+	now time.Time,
+	events []E,
+) []Event[E] {
+	result := make([]Event[E], 0, len(events))
+	for _, ev := range events {
+		version++
+		result = append(result, Event[E]{
+			AggregateID: aggregateID,
+			Version:     version,
+			Time:        now,
+			Data:        ev,
+		})
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+package eventbuswal
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	consumersDirName   = "consumers"
+	consumerOffsetExt  = ".offset"
+	temporaryFileExt   = ".tmp"
+	consumerOffsetPerm = 0o644
+)
+
+func (l *topicLog) consumerOffsetPath(name string) string {
+	return filepath.Join(l.dir, consumersDirName, url.PathEscape(name)+consumerOffsetExt)
+}
+
+// loadConsumerOffset returns the offset of the next event to be consumed
+// by the durable subscription of the given name.
+func (l *topicLog) loadConsumerOffset(name string) (uint64, bool, error) {
+	l.consumersLocker.Lock()
+	defer l.consumersLocker.Unlock()
+	path := l.consumerOffsetPath(name)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, false, nil
+		}
+		return 0, false, fmt.Errorf("unable to read '%s': %w", path, err)
+	}
+	offset, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("unable to parse '%s': %w", path, err)
+	}
+	return offset, true, nil
+}
+
+// storeConsumerOffset atomically replaces the stored offset of the durable
+// subscription of the given name.
+func (l *topicLog) storeConsumerOffset(name string, offset uint64) error {
+	l.consumersLocker.Lock()
+	defer l.consumersLocker.Unlock()
+	path := l.consumerOffsetPath(name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("unable to create directory '%s': %w", filepath.Dir(path), err)
+	}
+	tmpPath := path + temporaryFileExt
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, consumerOffsetPerm)
+	if err != nil {
+		return fmt.Errorf("unable to create '%s': %w", tmpPath, err)
+	}
+	_, err = f.WriteString(strconv.FormatUint(offset, 10))
+	if err == nil {
+		if _, ok := l.config.fsyncPolicy.(FSyncNever); !ok {
+			err = f.Sync()
+		}
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write '%s': %w", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("unable to rename '%s' to '%s': %w", tmpPath, path, err)
+	}
+	return nil
+}
+
+// consumerOffsets returns the stored offsets of all the durable subscriptions.
+func (l *topicLog) consumerOffsets() (map[string]uint64, error) {
+	dir := filepath.Join(l.dir, consumersDirName)
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]uint64{}, nil
+		}
+		return nil, fmt.Errorf("unable to read directory '%s': %w", dir, err)
+	}
+	result := map[string]uint64{}
+	for _, entry := range entries {
+		fileName := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(fileName, consumerOffsetExt) {
+			continue
+		}
+		name, err := url.PathUnescape(strings.TrimSuffix(fileName, consumerOffsetExt))
+		if err != nil {
+			continue
+		}
+		offset, ok, err := l.loadConsumerOffset(name)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			result[name] = offset
+		}
+	}
+	return result, nil
+}
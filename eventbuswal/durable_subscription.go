@@ -0,0 +1,67 @@
+package eventbuswal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/xaionaro-go/eventbus"
+)
+
+// DurableSubscription is a Subscription that remembers the last
+// consumed offset under a name, so that a subscription with the same
+// name resumes from there (e.g. after a process restart).
+type DurableSubscription[T, E any] struct {
+	*Subscription[T, E]
+	name string
+	log  *topicLog
+}
+
+// SubscribeDurable is the same as Subscribe, but the subscription resumes
+// from the offset stored under the given name (see DurableSubscription.Commit).
+// If there is no stored offset yet, initialOffset is used.
+func SubscribeDurable[T, E any](
+	ctx context.Context,
+	w *WAL,
+	bus *eventbus.EventBus,
+	topic T,
+	name string,
+	initialOffset uint64,
+	opts ...eventbus.Option,
+) (*DurableSubscription[T, E], error) {
+	l := w.getTopicLog(topic)
+	if l == nil {
+		return nil, fmt.Errorf("%w: %#+v", ErrTopicNotAdded, topic)
+	}
+	offset, ok, err := l.loadConsumerOffset(name)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load the offset of '%s': %w", name, err)
+	}
+	if !ok {
+		offset = initialOffset
+	}
+	sub, err := Subscribe[T, E](ctx, w, bus, topic, offset, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &DurableSubscription[T, E]{
+		Subscription: sub,
+		name:         name,
+		log:          l,
+	}, nil
+}
+
+// Name returns the name of the durable subscription.
+func (sub *DurableSubscription[T, E]) Name() string {
+	return sub.name
+}
+
+// Commit marks the record (and everything before it) as consumed,
+// so the subscription will resume after it.
+//
+// Records that are not persisted are ignored.
+func (sub *DurableSubscription[T, E]) Commit(rec Record[E]) error {
+	if !rec.Persisted {
+		return nil
+	}
+	return sub.log.storeConsumerOffset(sub.name, rec.Offset+1)
+}
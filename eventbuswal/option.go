@@ -0,0 +1,107 @@
+package eventbuswal
+
+import (
+	"context"
+	"time"
+)
+
+// FSyncPolicy defines when the written records are flushed to the disk.
+type FSyncPolicy interface {
+	isFSyncPolicy()
+}
+
+// FSyncAlways is a FSyncPolicy that flushes every record before
+// dispatching the event.
+type FSyncAlways struct{}
+
+var _ FSyncPolicy = FSyncAlways{}
+
+func (FSyncAlways) isFSyncPolicy() {}
+
+// FSyncNever is a FSyncPolicy that leaves flushing to the OS.
+type FSyncNever struct{}
+
+var _ FSyncPolicy = FSyncNever{}
+
+func (FSyncNever) isFSyncPolicy() {}
+
+// FSyncInterval is a FSyncPolicy that flushes the written records
+// periodically with the given interval.
+type FSyncInterval time.Duration
+
+var _ FSyncPolicy = FSyncInterval(0)
+
+func (FSyncInterval) isFSyncPolicy() {}
+
+type Option interface {
+	apply(*config)
+}
+
+type config struct {
+	fsyncPolicy    FSyncPolicy
+	maxSegmentSize int64
+	retentionSize  int64
+	retentionAge   time.Duration
+	onPersistError OptionOnPersistError
+}
+
+type Options []Option
+
+func (s Options) Config() config {
+	cfg := config{
+		fsyncPolicy:    FSyncInterval(time.Second),
+		maxSegmentSize: 64 << 20,
+	}
+	for _, opt := range s {
+		opt.apply(&cfg)
+	}
+	return cfg
+}
+
+type optionFSyncPolicyT struct {
+	FSyncPolicy
+}
+
+func OptionFSyncPolicy(v FSyncPolicy) optionFSyncPolicyT {
+	return optionFSyncPolicyT{
+		FSyncPolicy: v,
+	}
+}
+
+func (opt optionFSyncPolicyT) apply(cfg *config) {
+	cfg.fsyncPolicy = opt.FSyncPolicy
+}
+
+// OptionMaxSegmentSize sets the size of a segment file
+// after which a new segment is started.
+type OptionMaxSegmentSize int64
+
+func (opt OptionMaxSegmentSize) apply(cfg *config) {
+	cfg.maxSegmentSize = int64(opt)
+}
+
+// OptionRetentionSize sets the maximal total size of the segments of a topic;
+// the oldest segments are removed to fit. Zero means unlimited.
+type OptionRetentionSize int64
+
+func (opt OptionRetentionSize) apply(cfg *config) {
+	cfg.retentionSize = int64(opt)
+}
+
+// OptionRetentionAge sets for how long the segments of a topic are kept
+// after their last modification. Zero means forever.
+type OptionRetentionAge time.Duration
+
+func (opt OptionRetentionAge) apply(cfg *config) {
+	cfg.retentionAge = time.Duration(opt)
+}
+
+// OptionOnPersistError sets the function called (by the publisher) if
+// an event failed to be written to the WAL; by default the error is logged.
+//
+// The event is dispatched anyway, see Record.Persisted.
+type OptionOnPersistError func(ctx context.Context, topic, event any, err error)
+
+func (opt OptionOnPersistError) apply(cfg *config) {
+	cfg.onPersistError = opt
+}
@@ -0,0 +1,189 @@
+package eventbuswal
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/xaionaro-go/eventbus"
+)
+
+const (
+	// OffsetOldest is the offset to start from the oldest retained event.
+	OffsetOldest = uint64(0)
+
+	// OffsetNewest is the offset to receive only the events sent after subscribing.
+	OffsetNewest = uint64(math.MaxUint64)
+)
+
+// Record is an event received from a persisted topic.
+type Record[E any] struct {
+	Offset uint64
+	Time   time.Time
+
+	// Persisted is false if the event failed to be written to the WAL,
+	// in this case Offset and Time are meaningless.
+	Persisted bool
+
+	Event E
+}
+
+// Subscription is a subscription to a persisted topic (see Subscribe).
+type Subscription[T, E any] struct {
+	live      *eventbus.Subscription[T, eventbus.EventWithContext[E]]
+	eventChan chan Record[E]
+	cancelFn  context.CancelFunc
+	err       error
+}
+
+// LiveQueueSize and LivePileSize are the default queue size and
+// the default OnOverflowPileUpOrClose pile size of the live part
+// of a Subscription, which buffers the live events while the WAL is being read.
+const (
+	LiveQueueSize = 1024
+	LivePileSize  = 64 * 1024
+)
+
+// Subscribe subscribes to a persisted topic: first the events since
+// the given offset are read from the WAL, and then the live events
+// are received (without gaps and duplicates).
+//
+// The options are applied to the live subscription. The live events are
+// queued while the WAL is being read, so by default the live subscription
+// does not block the publishers (see LiveQueueSize) and is closed
+// with eventbus.ErrSubscriptionOverflow (see Err) if it is unable
+// to keep up.
+func Subscribe[T, E any](
+	ctx context.Context,
+	w *WAL,
+	bus *eventbus.EventBus,
+	topic T,
+	fromOffset uint64,
+	opts ...eventbus.Option,
+) (*Subscription[T, E], error) {
+	l := w.getTopicLog(topic)
+	if l == nil {
+		return nil, fmt.Errorf("%w: %#+v", ErrTopicNotAdded, topic)
+	}
+
+	ctx, cancelFn := context.WithCancel(ctx)
+	sub := &Subscription[T, E]{
+		eventChan: make(chan Record[E]),
+		cancelFn:  cancelFn,
+	}
+
+	// The events are appended to the WAL before the subscriptions to send
+	// them to are read (see eventbus.SendHook), thus each event at offset
+	// endOffset and later is received by the live subscription, and
+	// the earlier ones are read from the WAL (and skipped if received live).
+	opts = append(eventbus.Options{
+		eventbus.OptionQueueSize(LiveQueueSize),
+		eventbus.OptionOnOverflow(eventbus.OnOverflowPileUpOrClose(LivePileSize, 0)),
+	}, opts...)
+	var err error
+	sub.live, err = eventbus.SubscribeWithCustomTopicWithError[T, eventbus.EventWithContext[E]](ctx, bus, topic, opts...)
+	if err != nil {
+		cancelFn()
+		return nil, fmt.Errorf("unable to subscribe to topic %#+v: %w", topic, err)
+	}
+	endOffset := l.endOffset()
+	if fromOffset > endOffset {
+		fromOffset = endOffset
+	}
+
+	go func() {
+		defer close(sub.eventChan)
+		defer sub.live.Finish(context.Background())
+		sub.err = sub.serve(ctx, w, l, topic, fromOffset, endOffset)
+	}()
+	return sub, nil
+}
+
+func (sub *Subscription[T, E]) serve(
+	ctx context.Context,
+	w *WAL,
+	l *topicLog,
+	topic T,
+	fromOffset uint64,
+	endOffset uint64,
+) error {
+	err := l.read(fromOffset, endOffset, func(rec diskRecord) error {
+		_ev, err := w.registry.UnmarshalEvent(rec.Type, rec.Payload)
+		if err != nil {
+			return fmt.Errorf("unable to deserialize the event at offset %d: %w", rec.Offset, err)
+		}
+		ev, ok := _ev.(E)
+		if !ok {
+			return fmt.Errorf("the event at offset %d is of type %T, but expected %T", rec.Offset, _ev, ev)
+		}
+		return sub.send(ctx, Record[E]{
+			Offset:    rec.Offset,
+			Time:      rec.Time,
+			Persisted: true,
+			Event:     ev,
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		var (
+			ev eventbus.EventWithContext[E]
+			ok bool
+		)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok = <-sub.live.EventChan():
+			if !ok {
+				return sub.live.Err()
+			}
+		}
+		rec := Record[E]{
+			Event: ev.Event,
+		}
+		if info, ok := RecordInfoFromContext(ev.Context); ok && info.Topic == any(topic) {
+			if info.Offset < endOffset {
+				// was already read from the WAL
+				continue
+			}
+			rec.Offset = info.Offset
+			rec.Time = info.Time
+			rec.Persisted = true
+		}
+		if err := sub.send(ctx, rec); err != nil {
+			return err
+		}
+	}
+}
+
+func (sub *Subscription[T, E]) send(
+	ctx context.Context,
+	rec Record[E],
+) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case sub.eventChan <- rec:
+		return nil
+	}
+}
+
+// EventChan returns the channel of the events; it is closed when
+// the subscription is finished or failed (see Err).
+func (sub *Subscription[T, E]) EventChan() <-chan Record[E] {
+	return sub.eventChan
+}
+
+// Err returns the reason the subscription failed.
+// It may be called only after EventChan is closed.
+func (sub *Subscription[T, E]) Err() error {
+	return sub.err
+}
+
+// Finish cancels the subscription.
+func (sub *Subscription[T, E]) Finish() {
+	sub.cancelFn()
+}
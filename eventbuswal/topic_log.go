@@ -0,0 +1,387 @@
+package eventbuswal
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	segmentFileExt  = ".seg"
+	frameHeaderSize = 8
+)
+
+var ErrCorruptedRecord = errors.New("corrupted record")
+
+type diskRecord struct {
+	Offset  uint64    `json:"offset"`
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Payload []byte    `json:"payload"`
+}
+
+type segment struct {
+	baseOffset uint64
+	path       string
+	size       int64
+	modTime    time.Time
+}
+
+// topicLog is a segmented append-only log of a single topic.
+type topicLog struct {
+	locker     sync.Mutex
+	dir        string
+	config     config
+	segments   []*segment
+	active     *os.File
+	nextOffset uint64
+	dirty      bool
+
+	consumersLocker sync.Mutex
+}
+
+func openTopicLog(
+	dir string,
+	cfg config,
+) (*topicLog, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create directory '%s': %w", dir, err)
+	}
+	l := &topicLog{
+		dir:    dir,
+		config: cfg,
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read directory '%s': %w", dir, err)
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentFileExt) {
+			continue
+		}
+		baseOffset, err := strconv.ParseUint(strings.TrimSuffix(name, segmentFileExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("unable to stat '%s': %w", name, err)
+		}
+		l.segments = append(l.segments, &segment{
+			baseOffset: baseOffset,
+			path:       filepath.Join(dir, name),
+			size:       info.Size(),
+			modTime:    info.ModTime(),
+		})
+	}
+	sort.Slice(l.segments, func(i, j int) bool {
+		return l.segments[i].baseOffset < l.segments[j].baseOffset
+	})
+
+	if len(l.segments) == 0 {
+		if err := l.startSegment(0); err != nil {
+			return nil, err
+		}
+		return l, nil
+	}
+
+	last := l.segments[len(l.segments)-1]
+	l.nextOffset = last.baseOffset
+	validSize, err := scanSegment(last.path, func(rec diskRecord) error {
+		l.nextOffset = rec.Offset + 1
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrCorruptedRecord) {
+		return nil, err
+	}
+	// a partially written record (e.g. after a crash) is dropped
+	if err := os.Truncate(last.path, validSize); err != nil {
+		return nil, fmt.Errorf("unable to truncate '%s' to %d: %w", last.path, validSize, err)
+	}
+	last.size = validSize
+	l.active, err = os.OpenFile(last.path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("unable to open '%s': %w", last.path, err)
+	}
+	removeFiles(l.applyRetention(time.Now()))
+	return l, nil
+}
+
+func segmentPath(dir string, baseOffset uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%020d%s", baseOffset, segmentFileExt))
+}
+
+func (l *topicLog) startSegment(baseOffset uint64) error {
+	path := segmentPath(l.dir, baseOffset)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return fmt.Errorf("unable to create '%s': %w", path, err)
+	}
+	l.active = f
+	l.nextOffset = baseOffset
+	l.segments = append(l.segments, &segment{
+		baseOffset: baseOffset,
+		path:       path,
+		modTime:    time.Now(),
+	})
+	return nil
+}
+
+func (l *topicLog) append(
+	now time.Time,
+	typeName string,
+	payload []byte,
+) (uint64, error) {
+	var expired []string
+	defer func() { removeFiles(expired) }()
+	l.locker.Lock()
+	defer l.locker.Unlock()
+	if l.active == nil {
+		return 0, os.ErrClosed
+	}
+	offset := l.nextOffset
+	b, err := json.Marshal(diskRecord{
+		Offset:  offset,
+		Time:    now,
+		Type:    typeName,
+		Payload: payload,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("unable to serialize the record: %w", err)
+	}
+	frame := make([]byte, frameHeaderSize+len(b))
+	binary.BigEndian.PutUint32(frame[0:4], uint32(len(b)))
+	binary.BigEndian.PutUint32(frame[4:8], crc32.ChecksumIEEE(b))
+	copy(frame[frameHeaderSize:], b)
+	active := l.segments[len(l.segments)-1]
+	if _, err := l.active.Write(frame); err != nil {
+		err = fmt.Errorf("unable to write the record: %w", err)
+		// do not leave a partially written record, the next ones would be unreadable
+		if truncErr := os.Truncate(active.path, active.size); truncErr != nil {
+			l.active.Close()
+			l.active = nil
+			return 0, errors.Join(err, fmt.Errorf("unable to truncate '%s' to %d, the log is closed: %w", active.path, active.size, truncErr))
+		}
+		return 0, err
+	}
+	l.nextOffset++
+	active.size += int64(len(frame))
+	active.modTime = now
+	l.dirty = true
+
+	if _, ok := l.config.fsyncPolicy.(FSyncAlways); ok {
+		if err := l.syncLocked(); err != nil {
+			return offset, err
+		}
+	}
+	if l.config.maxSegmentSize > 0 && active.size >= l.config.maxSegmentSize {
+		if expired, err = l.roll(now); err != nil {
+			return offset, err
+		}
+	}
+	return offset, nil
+}
+
+// roll starts a new segment and returns the paths of the expired
+// segments to be removed (see applyRetention).
+func (l *topicLog) roll(now time.Time) ([]string, error) {
+	if err := l.syncLocked(); err != nil {
+		return nil, err
+	}
+	if err := l.active.Close(); err != nil {
+		return nil, fmt.Errorf("unable to close the segment: %w", err)
+	}
+	if err := l.startSegment(l.nextOffset); err != nil {
+		return nil, err
+	}
+	return l.applyRetention(now), nil
+}
+
+// retain is the same as applyRetention, but locks the log
+// and removes the expired segments.
+func (l *topicLog) retain(now time.Time) {
+	l.locker.Lock()
+	expired := l.applyRetention(now)
+	l.locker.Unlock()
+	removeFiles(expired)
+}
+
+// applyRetention forgets the old segments (except the active one) and
+// returns their paths, to be removed without the log locked.
+func (l *topicLog) applyRetention(now time.Time) []string {
+	var (
+		totalSize int64
+		expired   []string
+	)
+	for _, seg := range l.segments {
+		totalSize += seg.size
+	}
+	for len(l.segments) > 1 {
+		oldest := l.segments[0]
+		tooBig := l.config.retentionSize > 0 && totalSize > l.config.retentionSize
+		tooOld := l.config.retentionAge > 0 && now.Sub(oldest.modTime) > l.config.retentionAge
+		if !tooBig && !tooOld {
+			break
+		}
+		expired = append(expired, oldest.path)
+		totalSize -= oldest.size
+		l.segments = l.segments[1:]
+	}
+	return expired
+}
+
+func removeFiles(paths []string) {
+	for _, path := range paths {
+		// the readers handle the segments removed in the meanwhile (see read)
+		os.Remove(path)
+	}
+}
+
+func (l *topicLog) sync() error {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+	return l.syncLocked()
+}
+
+func (l *topicLog) syncLocked() error {
+	if !l.dirty || l.active == nil {
+		return nil
+	}
+	if err := l.active.Sync(); err != nil {
+		return fmt.Errorf("unable to fsync the segment: %w", err)
+	}
+	l.dirty = false
+	return nil
+}
+
+func (l *topicLog) close() error {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+	if l.active == nil {
+		return nil
+	}
+	err := l.syncLocked()
+	if closeErr := l.active.Close(); err == nil {
+		err = closeErr
+	}
+	l.active = nil
+	return err
+}
+
+// endOffset returns the offset the next record will have.
+func (l *topicLog) endOffset() uint64 {
+	l.locker.Lock()
+	defer l.locker.Unlock()
+	return l.nextOffset
+}
+
+// read calls fn for each record with offset in range [from, to).
+func (l *topicLog) read(
+	from, to uint64,
+	fn func(diskRecord) error,
+) error {
+	l.locker.Lock()
+	segments := make([]segment, 0, len(l.segments))
+	for _, seg := range l.segments {
+		segments = append(segments, *seg)
+	}
+	l.locker.Unlock()
+
+	if from >= to {
+		return nil
+	}
+	errStop := errors.New("stop")
+	for idx, seg := range segments {
+		if seg.baseOffset >= to {
+			break
+		}
+		if idx+1 < len(segments) && segments[idx+1].baseOffset <= from {
+			continue
+		}
+		_, err := scanSegment(seg.path, func(rec diskRecord) error {
+			if rec.Offset >= to {
+				return errStop
+			}
+			if rec.Offset >= from {
+				if err := fn(rec); err != nil {
+					return err
+				}
+			}
+			if rec.Offset+1 >= to {
+				// not reading further, since the next record might be being written right now
+				return errStop
+			}
+			return nil
+		})
+		switch {
+		case err == nil, errors.Is(err, errStop):
+		case errors.Is(err, os.ErrNotExist):
+			// removed by the retention in the meanwhile
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+// scanSegment calls fn for each record of the segment file, and
+// returns the size of the successfully read part of the file.
+func scanSegment(
+	path string,
+	fn func(diskRecord) error,
+) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("unable to open '%s': %w", path, err)
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("unable to stat '%s': %w", path, err)
+	}
+	r := bufio.NewReader(f)
+	var (
+		pos    int64
+		header [frameHeaderSize]byte
+	)
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return pos, nil
+			}
+			return pos, fmt.Errorf("%w: '%s' at %d: unable to read the header: %w", ErrCorruptedRecord, path, pos, err)
+		}
+		size := binary.BigEndian.Uint32(header[0:4])
+		if int64(size) > stat.Size()-pos-frameHeaderSize {
+			// not trusting the size before allocating the buffer
+			return pos, fmt.Errorf("%w: '%s' at %d: the record size %d exceeds the file size %d", ErrCorruptedRecord, path, pos, size, stat.Size())
+		}
+		b := make([]byte, size)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return pos, fmt.Errorf("%w: '%s' at %d: unable to read the body: %w", ErrCorruptedRecord, path, pos, err)
+		}
+		if crc32.ChecksumIEEE(b) != binary.BigEndian.Uint32(header[4:8]) {
+			return pos, fmt.Errorf("%w: '%s' at %d: checksum mismatch", ErrCorruptedRecord, path, pos)
+		}
+		var rec diskRecord
+		if err := json.Unmarshal(b, &rec); err != nil {
+			return pos, fmt.Errorf("%w: '%s' at %d: %w", ErrCorruptedRecord, path, pos, err)
+		}
+		if err := fn(rec); err != nil {
+			return pos, err
+		}
+		pos += int64(frameHeaderSize) + int64(size)
+	}
+}
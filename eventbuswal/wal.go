@@ -0,0 +1,304 @@
+// Package eventbuswal provides an optional persistence layer for an EventBus:
+// the events sent to the configured topics are appended to an on-disk
+// segmented log before they are dispatched, and subscribers may start
+// receiving events from an arbitrary offset of the log.
+//
+// Usage:
+//
+//	reg := eventbuscodec.NewRegistry()
+//	eventbuscodec.RegisterEventType[MyEvent](reg, "my-event", eventbuscodec.JSON{})
+//	wal, err := eventbuswal.Open(dir, reg)
+//	...
+//	err = wal.AddTopic(MyEvent{})
+//	...
+//	bus := eventbus.New(wal.BusOptions()...)
+//	sub, err := eventbuswal.Subscribe[MyEvent, MyEvent](ctx, wal, bus, MyEvent{}, eventbuswal.OffsetOldest)
+package eventbuswal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/facebookincubator/go-belt/tool/logger"
+	"github.com/xaionaro-go/eventbus"
+	"github.com/xaionaro-go/eventbus/eventbuscodec"
+)
+
+var (
+	ErrTopicNotRegistered = errors.New("the topic is not registered in the codec registry")
+	ErrTopicNotAdded      = errors.New("the topic is not added to the WAL")
+)
+
+// WAL is a write-ahead log of topics of an EventBus.
+type WAL struct {
+	dir      string
+	registry *eventbuscodec.Registry
+	config   config
+	locker   sync.Mutex
+	topics   map[any]*topicLog
+	closer   context.CancelFunc
+	wg       sync.WaitGroup
+}
+
+var _ eventbus.SendHook = (*WAL)(nil)
+var _ eventbus.ContextPropagator = (*WAL)(nil)
+
+// Open opens (or creates) a WAL in the given directory.
+//
+// The topics are serialized using the given codec registry, thus
+// each topic to be persisted should be registered there.
+func Open(
+	dir string,
+	registry *eventbuscodec.Registry,
+	opts ...Option,
+) (*WAL, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create directory '%s': %w", dir, err)
+	}
+	ctx, cancelFn := context.WithCancel(context.Background())
+	w := &WAL{
+		dir:      dir,
+		registry: registry,
+		config:   Options(opts).Config(),
+		topics:   map[any]*topicLog{},
+		closer:   cancelFn,
+	}
+	var syncInterval time.Duration
+	if interval, ok := w.config.fsyncPolicy.(FSyncInterval); ok {
+		syncInterval = time.Duration(interval)
+	}
+	if syncInterval > 0 || w.config.retentionAge > 0 {
+		w.wg.Add(1)
+		go func() {
+			defer w.wg.Done()
+			w.maintenanceLoop(ctx, syncInterval, w.config.retentionAge)
+		}()
+	}
+	return w, nil
+}
+
+// AddTopic makes the WAL to persist the events of the given topic.
+func (w *WAL) AddTopic(topic any) error {
+	name, ok := w.registry.TopicName(topic)
+	if !ok {
+		return fmt.Errorf("%w: %#+v", ErrTopicNotRegistered, topic)
+	}
+	w.locker.Lock()
+	defer w.locker.Unlock()
+	if _, ok := w.topics[topic]; ok {
+		return nil
+	}
+	l, err := openTopicLog(filepath.Join(w.dir, url.PathEscape(name)), w.config)
+	if err != nil {
+		return fmt.Errorf("unable to open the log of topic '%s': %w", name, err)
+	}
+	w.topics[topic] = l
+	return nil
+}
+
+// Topics returns the topics added to the WAL (see AddTopic).
+func (w *WAL) Topics() []any {
+	w.locker.Lock()
+	defer w.locker.Unlock()
+	result := make([]any, 0, len(w.topics))
+	for topic := range w.topics {
+		result = append(result, topic)
+	}
+	return result
+}
+
+func (w *WAL) getTopicLog(topic any) *topicLog {
+	w.locker.Lock()
+	defer w.locker.Unlock()
+	return w.topics[topic]
+}
+
+// maintenanceLoop periodically flushes the written records (if syncInterval
+// is not zero) and removes the segments older than retentionAge (if not zero),
+// so that the segments expire even if nothing is written.
+func (w *WAL) maintenanceLoop(
+	ctx context.Context,
+	syncInterval time.Duration,
+	retentionAge time.Duration,
+) {
+	interval := syncInterval
+	if retentionAge > 0 && (interval == 0 || retentionAge < interval) {
+		interval = retentionAge
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if syncInterval > 0 {
+			if err := w.Sync(); err != nil {
+				logger.Errorf(ctx, "unable to sync the WAL: %v", err)
+			}
+		}
+		if retentionAge > 0 {
+			w.applyRetention(time.Now())
+		}
+	}
+}
+
+func (w *WAL) applyRetention(now time.Time) {
+	w.locker.Lock()
+	logs := make([]*topicLog, 0, len(w.topics))
+	for _, l := range w.topics {
+		logs = append(logs, l)
+	}
+	w.locker.Unlock()
+	for _, l := range logs {
+		l.retain(now)
+	}
+}
+
+// Sync flushes all the written records to the disk.
+func (w *WAL) Sync() error {
+	w.locker.Lock()
+	defer w.locker.Unlock()
+	var result error
+	for _, l := range w.topics {
+		result = errors.Join(result, l.sync())
+	}
+	return result
+}
+
+// Close flushes and closes the logs of all the topics.
+func (w *WAL) Close() error {
+	w.closer()
+	w.wg.Wait()
+	w.locker.Lock()
+	defer w.locker.Unlock()
+	var result error
+	for _, l := range w.topics {
+		result = errors.Join(result, l.close())
+	}
+	return result
+}
+
+// EndOffset returns the offset the next event of the topic will have.
+func (w *WAL) EndOffset(topic any) (uint64, error) {
+	l := w.getTopicLog(topic)
+	if l == nil {
+		return 0, fmt.Errorf("%w: %#+v", ErrTopicNotAdded, topic)
+	}
+	return l.endOffset(), nil
+}
+
+// ConsumerOffsets returns the stored offsets of all durable subscriptions
+// of the topic (see SubscribeDurable) by their names.
+func (w *WAL) ConsumerOffsets(topic any) (map[string]uint64, error) {
+	l := w.getTopicLog(topic)
+	if l == nil {
+		return nil, fmt.Errorf("%w: %#+v", ErrTopicNotAdded, topic)
+	}
+	return l.consumerOffsets()
+}
+
+// SetConsumerOffset overrides the stored offset of the durable subscription
+// of the given name (see SubscribeDurable).
+func (w *WAL) SetConsumerOffset(topic any, name string, offset uint64) error {
+	l := w.getTopicLog(topic)
+	if l == nil {
+		return fmt.Errorf("%w: %#+v", ErrTopicNotAdded, topic)
+	}
+	return l.storeConsumerOffset(name, offset)
+}
+
+// BusOptions returns the options to be passed to eventbus.New
+// to enable the WAL.
+func (w *WAL) BusOptions() eventbus.BusOptions {
+	return eventbus.BusOptions{
+		eventbus.BusOptionSendHook{SendHook: w},
+		eventbus.BusOptionContextPropagator{ContextPropagator: w},
+	}
+}
+
+// RecordInfo describes the place of an event in the WAL.
+type RecordInfo struct {
+	Topic  any
+	Offset uint64
+	Time   time.Time
+}
+
+type recordInfoCtxKey struct{}
+
+// RecordInfoFromContext returns the RecordInfo of the event being sent
+// (or received, see eventbus.SubscribeWithContext).
+func RecordInfoFromContext(ctx context.Context) (RecordInfo, bool) {
+	info, ok := ctx.Value(recordInfoCtxKey{}).(RecordInfo)
+	return info, ok
+}
+
+// BeforeSend implements eventbus.SendHook.
+func (w *WAL) BeforeSend(
+	ctx context.Context,
+	topic, event any,
+) context.Context {
+	l := w.getTopicLog(topic)
+	if l == nil {
+		return ctx
+	}
+	// resetting the info of a previous event (e.g. if the event is sent
+	// by a subscriber of another persisted topic)
+	ctx = context.WithValue(ctx, recordInfoCtxKey{}, nil)
+
+	typeName, payload, err := w.registry.MarshalEvent(event)
+	if err != nil {
+		w.onPersistError(ctx, topic, event, fmt.Errorf("unable to serialize: %w", err))
+		return ctx
+	}
+	now := time.Now()
+	offset, err := l.append(now, typeName, payload)
+	if err != nil {
+		w.onPersistError(ctx, topic, event, err)
+		return ctx
+	}
+	return context.WithValue(ctx, recordInfoCtxKey{}, RecordInfo{
+		Topic:  topic,
+		Offset: offset,
+		Time:   now,
+	})
+}
+
+func (w *WAL) onPersistError(
+	ctx context.Context,
+	topic, event any,
+	err error,
+) {
+	if w.config.onPersistError != nil {
+		w.config.onPersistError(ctx, topic, event, err)
+		return
+	}
+	logger.Errorf(ctx, "unable to persist an event of topic %#+v: %v", topic, err)
+}
+
+// AfterSend implements eventbus.SendHook.
+func (w *WAL) AfterSend(
+	ctx context.Context,
+	topic, event any,
+	result eventbus.SendEventResult,
+) {
+}
+
+// PropagateContext implements eventbus.ContextPropagator.
+func (w *WAL) PropagateContext(
+	publisherCtx context.Context,
+	deliveryCtx context.Context,
+) context.Context {
+	info, ok := RecordInfoFromContext(publisherCtx)
+	if !ok {
+		return deliveryCtx
+	}
+	return context.WithValue(deliveryCtx, recordInfoCtxKey{}, info)
+}
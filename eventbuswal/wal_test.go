@@ -0,0 +1,325 @@
+package eventbuswal
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/xaionaro-go/eventbus"
+	"github.com/xaionaro-go/eventbus/eventbuscodec"
+)
+
+type testEvent struct {
+	Value int
+}
+
+func newTestWAL(t *testing.T, dir string, opts ...Option) (*WAL, *eventbus.EventBus) {
+	reg := eventbuscodec.NewRegistry()
+	require.NoError(t, eventbuscodec.RegisterEventType[testEvent](reg, "test-event", eventbuscodec.JSON{}))
+	wal, err := Open(dir, reg, opts...)
+	require.NoError(t, err)
+	require.NoError(t, wal.AddTopic(testEvent{}))
+	return wal, eventbus.New(wal.BusOptions()...)
+}
+
+func TestWAL(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+	dir := t.TempDir()
+
+	wal, bus := newTestWAL(t, dir, OptionFSyncPolicy(FSyncAlways{}))
+	for i := range 3 {
+		eventbus.SendEvent(ctx, bus, testEvent{Value: i})
+	}
+	require.NoError(t, wal.Close())
+
+	// restart
+	wal, bus = newTestWAL(t, dir)
+	defer wal.Close()
+	endOffset, err := wal.EndOffset(testEvent{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), endOffset)
+
+	sub, err := Subscribe[testEvent, testEvent](ctx, wal, bus, testEvent{}, 1)
+	require.NoError(t, err)
+	defer sub.Finish()
+	for i := 1; i < 3; i++ {
+		rec := <-sub.EventChan()
+		require.True(t, rec.Persisted)
+		require.Equal(t, uint64(i), rec.Offset)
+		require.Equal(t, testEvent{Value: i}, rec.Event)
+	}
+
+	eventbus.SendEvent(ctx, bus, testEvent{Value: 3})
+	rec := <-sub.EventChan()
+	require.True(t, rec.Persisted)
+	require.Equal(t, uint64(3), rec.Offset)
+	require.Equal(t, testEvent{Value: 3}, rec.Event)
+}
+
+func TestWALSubscribeWhilePublishing(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+
+	wal, bus := newTestWAL(t, t.TempDir())
+	defer wal.Close()
+
+	const count = 1000
+	go func() {
+		for i := range count {
+			eventbus.SendEvent(ctx, bus, testEvent{Value: i})
+			if i%10 == 0 {
+				runtime.Gosched()
+			}
+		}
+	}()
+	for range 10 {
+		runtime.Gosched()
+	}
+
+	sub, err := Subscribe[testEvent, testEvent](ctx, wal, bus, testEvent{}, OffsetOldest)
+	require.NoError(t, err)
+	defer sub.Finish()
+	for i := range count {
+		rec := <-sub.EventChan()
+		require.True(t, rec.Persisted)
+		require.Equal(t, uint64(i), rec.Offset)
+		require.Equal(t, testEvent{Value: i}, rec.Event)
+	}
+}
+
+func TestWALSubscribeCallbackPublishing(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+
+	wal, bus := newTestWAL(t, t.TempDir())
+	defer wal.Close()
+
+	// publishing from a subscription callback is not supposed to deadlock on the log
+	sub, err := Subscribe[testEvent, testEvent](ctx, wal, bus, testEvent{}, OffsetOldest,
+		eventbus.OptionBeforeSubscribed[testEvent, eventbus.EventWithContext[testEvent]](
+			func(ctx context.Context, _ *eventbus.Subscription[testEvent, eventbus.EventWithContext[testEvent]]) {
+				eventbus.SendEvent(ctx, bus, testEvent{Value: 0})
+			},
+		),
+	)
+	require.NoError(t, err)
+	defer sub.Finish()
+	eventbus.SendEvent(ctx, bus, testEvent{Value: 1})
+	for i := range 2 {
+		rec := <-sub.EventChan()
+		require.Equal(t, uint64(i), rec.Offset)
+		require.Equal(t, testEvent{Value: i}, rec.Event)
+	}
+}
+
+func TestWALSubscribeLiveBuffering(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancelFn()
+
+	wal, bus := newTestWAL(t, t.TempDir())
+	defer wal.Close()
+	eventbus.SendEvent(ctx, bus, testEvent{Value: 0})
+
+	sub, err := Subscribe[testEvent, testEvent](ctx, wal, bus, testEvent{}, OffsetOldest)
+	require.NoError(t, err)
+	defer sub.Finish()
+
+	// the publishers are not blocked by the subscriber reading the WAL
+	for i := 1; i < 100; i++ {
+		r := eventbus.SendEvent(ctx, bus, testEvent{Value: i})
+		require.Zero(t, r.DropCountImmediate+r.DropCountDeferred)
+	}
+	for i := range 100 {
+		rec := <-sub.EventChan()
+		require.Equal(t, uint64(i), rec.Offset)
+		require.Equal(t, testEvent{Value: i}, rec.Event)
+	}
+
+	closedBus := eventbus.New(wal.BusOptions()...)
+	require.NoError(t, closedBus.Close(ctx))
+	_, err = Subscribe[testEvent, testEvent](ctx, wal, closedBus, testEvent{}, OffsetOldest)
+	require.ErrorIs(t, err, eventbus.ErrBusClosed)
+}
+
+func TestWALSegmentsAndRetention(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	wal, bus := newTestWAL(t, dir, OptionMaxSegmentSize(1), OptionRetentionSize(1000))
+	defer wal.Close()
+	for i := range 100 {
+		eventbus.SendEvent(ctx, bus, testEvent{Value: i})
+	}
+	segments, err := filepath.Glob(filepath.Join(dir, "test-event", "*"+segmentFileExt))
+	require.NoError(t, err)
+	require.Less(t, len(segments), 100)
+
+	var totalSize int64
+	for _, path := range segments {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		totalSize += info.Size()
+	}
+	require.LessOrEqual(t, totalSize, int64(1000))
+
+	sub, err := Subscribe[testEvent, testEvent](ctx, wal, bus, testEvent{}, OffsetOldest)
+	require.NoError(t, err)
+	defer sub.Finish()
+	rec := <-sub.EventChan()
+	require.Greater(t, rec.Offset, uint64(0))
+	require.Equal(t, testEvent{Value: int(rec.Offset)}, rec.Event)
+}
+
+func TestWALRetentionAge(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	wal, bus := newTestWAL(t, dir, OptionFSyncPolicy(FSyncNever{}), OptionMaxSegmentSize(1), OptionRetentionAge(50*time.Millisecond))
+	defer wal.Close()
+	for i := range 5 {
+		eventbus.SendEvent(ctx, bus, testEvent{Value: i})
+	}
+	countSegments := func() int {
+		segments, err := filepath.Glob(filepath.Join(dir, "test-event", "*"+segmentFileExt))
+		require.NoError(t, err)
+		return len(segments)
+	}
+	require.Equal(t, 6, countSegments())
+
+	// the old segments are removed even if nothing is written
+	require.Eventually(t, func() bool {
+		return countSegments() == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	endOffset, err := wal.EndOffset(testEvent{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), endOffset)
+}
+
+func TestWALPartialRecordRecovery(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	wal, bus := newTestWAL(t, dir)
+	eventbus.SendEvent(ctx, bus, testEvent{Value: 0})
+	require.NoError(t, wal.Close())
+
+	path := segmentPath(filepath.Join(dir, "test-event"), 0)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0, 0, 1, 0, 1, 2})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	_, err = scanSegment(path, func(diskRecord) error { return nil })
+	require.ErrorIs(t, err, ErrCorruptedRecord)
+
+	// a garbage size is not trusted
+	corruptedPath := filepath.Join(t.TempDir(), "corrupted"+segmentFileExt)
+	require.NoError(t, os.WriteFile(corruptedPath, []byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0}, 0o644))
+	validSize, err := scanSegment(corruptedPath, func(diskRecord) error { return nil })
+	require.ErrorIs(t, err, ErrCorruptedRecord)
+	require.Zero(t, validSize)
+
+	wal, bus = newTestWAL(t, dir)
+	defer wal.Close()
+	eventbus.SendEvent(ctx, bus, testEvent{Value: 1})
+	endOffset, err := wal.EndOffset(testEvent{})
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), endOffset)
+
+	sub, err := Subscribe[testEvent, testEvent](ctx, wal, bus, testEvent{}, OffsetOldest)
+	require.NoError(t, err)
+	defer sub.Finish()
+	require.Equal(t, testEvent{Value: 0}, (<-sub.EventChan()).Event)
+	require.Equal(t, testEvent{Value: 1}, (<-sub.EventChan()).Event)
+}
+
+func TestWALWriteFailure(t *testing.T) {
+	dir := t.TempDir()
+	l, err := openTopicLog(dir, Options{}.Config())
+	require.NoError(t, err)
+	defer l.close()
+	_, err = l.append(time.Now(), "test-event", []byte(`{"Value":0}`))
+	require.NoError(t, err)
+
+	// simulate a partially written record followed by a write failure
+	path := segmentPath(dir, 0)
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0, 0, 1, 0, 1, 2})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	writable := l.active
+	l.active, err = os.Open(path)
+	require.NoError(t, err)
+	_, err = l.append(time.Now(), "test-event", []byte(`{"Value":1}`))
+	require.Error(t, err)
+	require.NoError(t, l.active.Close())
+	l.active = writable
+	require.Equal(t, uint64(1), l.endOffset())
+
+	offset, err := l.append(time.Now(), "test-event", []byte(`{"Value":1}`))
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), offset)
+	var offsets []uint64
+	require.NoError(t, l.read(0, 2, func(rec diskRecord) error {
+		offsets = append(offsets, rec.Offset)
+		return nil
+	}))
+	require.Equal(t, []uint64{0, 1}, offsets)
+}
+
+func TestWALPersistError(t *testing.T) {
+	ctx := context.Background()
+	var persistErr error
+	wal, bus := newTestWAL(t, t.TempDir(), OptionOnPersistError(func(ctx context.Context, topic, event any, err error) {
+		persistErr = err
+	}))
+	sub := eventbus.SubscribeWithContext[testEvent](ctx, bus, eventbus.OptionQueueSize(1))
+	defer sub.Finish(ctx)
+	require.NoError(t, wal.Close())
+
+	require.Equal(t, uint(1), eventbus.SendEvent(ctx, bus, testEvent{Value: 1}).SentCountImmediate)
+	require.ErrorIs(t, persistErr, os.ErrClosed)
+	_, ok := RecordInfoFromContext((<-sub.EventChan()).Context)
+	require.False(t, ok)
+}
+
+func TestDurableSubscription(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	wal, bus := newTestWAL(t, dir)
+	for i := range 3 {
+		eventbus.SendEvent(ctx, bus, testEvent{Value: i})
+	}
+	sub, err := SubscribeDurable[testEvent, testEvent](ctx, wal, bus, testEvent{}, "consumer", OffsetOldest)
+	require.NoError(t, err)
+	rec := <-sub.EventChan()
+	require.Equal(t, testEvent{Value: 0}, rec.Event)
+	require.NoError(t, sub.Commit(rec))
+	sub.Finish()
+	for range sub.EventChan() {
+	}
+	eventbus.SendEvent(ctx, bus, testEvent{Value: 3})
+	require.NoError(t, wal.Close())
+
+	// restart
+	wal, bus = newTestWAL(t, dir)
+	defer wal.Close()
+	offsets, err := wal.ConsumerOffsets(testEvent{})
+	require.NoError(t, err)
+	require.Equal(t, map[string]uint64{"consumer": 1}, offsets)
+
+	sub, err = SubscribeDurable[testEvent, testEvent](ctx, wal, bus, testEvent{}, "consumer", OffsetNewest)
+	require.NoError(t, err)
+	defer sub.Finish()
+	for i := 1; i <= 3; i++ {
+		rec := <-sub.EventChan()
+		require.Equal(t, testEvent{Value: i}, rec.Event)
+	}
+}
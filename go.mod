@@ -2,14 +2,21 @@ module github.com/xaionaro-go/eventbus
 
 go 1.24.1
 
-require github.com/stretchr/testify v1.10.0
+require (
+	github.com/fxamacker/cbor/v2 v2.7.0
+	github.com/stretchr/testify v1.10.0
+)
 
 require (
 	github.com/go-ng/slices v0.0.0-20230703171042-6195d35636a2 // indirect
 	github.com/go-ng/sort v0.0.0-20220617173827-2cc7cd04f7c7 // indirect
 	github.com/go-ng/xsort v0.0.0-20220617174223-1d146907bccc // indirect
 	github.com/google/uuid v1.6.0 // indirect
+	github.com/kr/pretty v0.3.1 // indirect
+	github.com/rogpeppe/go-internal v1.13.1 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
 	golang.org/x/exp v0.0.0-20230519143937-03e91628a987 // indirect
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c // indirect
 )
 
 require (
@@ -60,3 +60,32 @@ func OnOverflowPileUpOrClose(
 }
 
 func (onOverflowPileUpOrClose) isOverflow() {}
+
+type onOverflowSpillToDisk struct {
+	MemoryPileSize uint
+	Dir            string
+	Codec          SpillCodec
+}
+
+var _ OnOverflow = onOverflowSpillToDisk{}
+
+// OnOverflowSpillToDisk is an OnOverflow that (similar to OnOverflowPileUpOrClose)
+// piles up the events the subscriber is not ready to receive, but keeps in memory
+// only up to memoryPileSize events and writes the rest to a temporary file in
+// the given directory (or in os.TempDir if dir is empty) using the given codec.
+// The events are fed to the subscriber in the original order.
+//
+// The subscription is closed if unable to spill an event.
+func OnOverflowSpillToDisk(
+	memoryPileSize uint,
+	dir string,
+	codec SpillCodec,
+) onOverflowSpillToDisk {
+	return onOverflowSpillToDisk{
+		MemoryPileSize: memoryPileSize,
+		Dir:            dir,
+		Codec:          codec,
+	}
+}
+
+func (onOverflowSpillToDisk) isOverflow() {}
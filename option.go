@@ -13,11 +13,14 @@ type SubscriptionCallback[T, E any] func(context.Context, *Subscription[T, E])
 type abstractSubscriptionCallback any
 
 type config struct {
-	onOverflow       OnOverflow
-	beforeSubscribed abstractSubscriptionCallback
-	onSubscribed     abstractSubscriptionCallback
-	onUnsubscribe    abstractSubscriptionCallback
-	queueSize        uint
+	onOverflow         OnOverflow
+	beforeSubscribed   abstractSubscriptionCallback
+	onSubscribed       abstractSubscriptionCallback
+	onUnsubscribe      abstractSubscriptionCallback
+	queueSize          uint
+	contextPropagators []ContextPropagator
+	receiveRetained    bool
+	orderedDelivery    bool
 }
 
 type Options []Option
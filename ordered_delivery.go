@@ -0,0 +1,45 @@
+package eventbus
+
+// OptionOrderedDelivery makes the events of each publisher to reach
+// the subscription in the order they were sent, even if some of them
+// are deferred (see OnOverflowWait, OnOverflowWaitOrClose and SendEventAsync):
+// an event is not sent to the subscription while an earlier deferred send
+// to it is pending, and the deferred sends are performed one by one in
+// the order they were started.
+//
+// The events piled up by OnOverflowPileUpOrClose and OnOverflowSpillToDisk
+// are delivered in order regardless of this option.
+type OptionOrderedDelivery bool
+
+func (opt OptionOrderedDelivery) apply(cfg *config) {
+	cfg.orderedDelivery = bool(opt)
+}
+
+// hasPendingDeferredSendLocked is to be called with orderLocker locked.
+func (sub *Subscription[T, E]) hasPendingDeferredSendLocked() bool {
+	return sub.lastDeferredSendDone != nil && !isChanClosed(sub.lastDeferredSendDone)
+}
+
+// enqueueDeferredSend registers a deferred send to the subscription
+// and returns the channel to wait for before sending (nil if
+// there is no need to wait) and the channel to be closed when
+// the deferred send is finished (either way).
+func (sub *Subscription[T, E]) enqueueDeferredSend() (turn <-chan struct{}, done chan struct{}) {
+	sub.orderLocker.Lock()
+	defer sub.orderLocker.Unlock()
+	if sub.hasPendingDeferredSendLocked() {
+		turn = sub.lastDeferredSendDone
+	}
+	done = make(chan struct{})
+	sub.lastDeferredSendDone = done
+	return turn, done
+}
+
+func isChanClosed(ch <-chan struct{}) bool {
+	select {
+	case <-ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,91 @@
+package eventbus
+
+import (
+	"context"
+
+	"github.com/facebookincubator/go-belt/tool/logger"
+)
+
+// BusOptionRetainLastEvents makes the EventBus to remember the last event
+// sent to each topic (see EventBus.RetainedEvent and OptionReceiveRetained).
+type BusOptionRetainLastEvents bool
+
+func (opt BusOptionRetainLastEvents) applyToBus(cfg *busConfig) {
+	cfg.retainLastEvents = bool(opt)
+}
+
+// OptionReceiveRetained makes the subscription to receive the retained
+// event of the topic (if any) right after subscribing, before any other event
+// (see BusOptionRetainLastEvents).
+//
+// It has no effect on subscriptions receiving EventWithContext
+// and on subscriptions with zero queue size.
+type OptionReceiveRetained bool
+
+func (opt OptionReceiveRetained) apply(cfg *config) {
+	cfg.receiveRetained = bool(opt)
+}
+
+// RetainedEvent returns the last event sent to the topic
+// (see BusOptionRetainLastEvents).
+func (bus *EventBus) RetainedEvent(topic any) (any, bool) {
+	state := bus.getTopicState(topic)
+	if state == nil {
+		return nil, false
+	}
+	state.retainedLocker.Lock()
+	defer state.retainedLocker.Unlock()
+	return state.retained, state.hasRetained
+}
+
+// RetainedEvents returns the last events sent to each topic
+// (see BusOptionRetainLastEvents).
+func (bus *EventBus) RetainedEvents() map[any]any {
+	result := map[any]any{}
+	bus.topics.Range(func(topic, _state any) bool {
+		state := _state.(*topicState)
+		state.retainedLocker.Lock()
+		defer state.retainedLocker.Unlock()
+		if state.hasRetained {
+			result[topic] = state.retained
+		}
+		return true
+	})
+	return result
+}
+
+// SetRetainedEvent sets the retained event of the topic without sending it
+// (e.g. to restore the state after a restart). The event should be of
+// the type used by the subscribers of the topic.
+func (bus *EventBus) SetRetainedEvent(topic, event any) {
+	state := bus.lockTopicStateRetained(topic)
+	defer state.retainedLocker.Unlock()
+	state.retained, state.hasRetained = event, true
+}
+
+// sendRetainedEvent is to be called with state.retainedLocker locked
+// right after adding the subscription to the topic.
+func (sub *Subscription[T, E]) sendRetainedEvent(
+	ctx context.Context,
+	state *topicState,
+) {
+	if !state.hasRetained {
+		return
+	}
+	_event := state.retained
+	event, ok := _event.(E)
+	if !ok {
+		if isTraceEnabled(ctx) {
+			logger.Tracef(ctx, "the retained event is of type %T, not %T", _event, event)
+		}
+		return
+	}
+	// the subscription is just created and the retaining publishers are waiting
+	// for retainedLocker, so the retained event goes first
+	sub.addDeliveredCount(1)
+	select {
+	case sub.queue <- event:
+	default:
+		sub.addDeliveredCount(-1)
+	}
+}
@@ -0,0 +1,84 @@
+package eventbus
+
+import (
+	"context"
+)
+
+// SendEventHandle is a handle of an event sent by SendEventAsync.
+type SendEventHandle struct {
+	immediateResult SendEventResult
+	result          SendEventResult
+	err             error
+	done            chan struct{}
+}
+
+// ImmediateResult returns the result of the immediate (non-blocking) phase
+// of sending the event (thus SentCountDeferred and DropCountDeferred are zero).
+func (h *SendEventHandle) ImmediateResult() SendEventResult {
+	return h.immediateResult
+}
+
+// Done returns a channel which is closed when the sending is complete
+// (including the deferred sends).
+func (h *SendEventHandle) Done() <-chan struct{} {
+	return h.done
+}
+
+// Result waits until the sending is complete and returns the final result
+// and error (the same as SendEventWithCustomTopicWithError would return).
+//
+// It returns ctx.Err() if ctx is done before the sending is complete,
+// and this does not cancel the sending.
+func (h *SendEventHandle) Result(ctx context.Context) (SendEventResult, error) {
+	select {
+	case <-ctx.Done():
+		return SendEventResult{}, ctx.Err()
+	case <-h.done:
+		return h.result, h.err
+	}
+}
+
+// SendEventAsync is the same as SendEvent, but it returns right after
+// the immediate phase without waiting for the deferred sends (see OnOverflowWait).
+func SendEventAsync[E any](
+	ctx context.Context,
+	bus *EventBus,
+	event E,
+) *SendEventHandle {
+	var zeroValue E
+	return SendEventAsyncWithCustomTopic(ctx, bus, zeroValue, event)
+}
+
+// SendEventAsyncWithCustomTopic is the same as SendEventWithCustomTopic,
+// but it returns right after the immediate phase without waiting for
+// the deferred sends (see OnOverflowWait). The deferred sends are performed
+// in a separate goroutine and are dropped if ctx is done, thus
+// ctx should outlive the sending.
+//
+// Note that the deferred sends of different calls are not ordered
+// relative to each other, unless the subscription has OptionOrderedDelivery.
+func SendEventAsyncWithCustomTopic[T, E any](
+	ctx context.Context,
+	bus *EventBus,
+	topic T,
+	event E,
+) *SendEventHandle {
+	s := &pendingSend[T, E]{bus: bus, topic: topic, event: event}
+	s.begin(ctx)
+	h := &SendEventHandle{
+		immediateResult: s.result,
+		done:            make(chan struct{}),
+	}
+	if len(s.deferredSends) == 0 {
+		s.finish()
+		h.result, h.err = s.result, s.err
+		close(h.done)
+		return h
+	}
+	go func() {
+		defer close(h.done)
+		s.finish()
+		h.result, h.err = s.result, s.err
+	}()
+	return h
+}
@@ -0,0 +1,123 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/facebookincubator/go-belt"
+	"github.com/facebookincubator/go-belt/tool/logger"
+)
+
+// SendEventsResult is the result of SendEventsWithCustomTopic.
+type SendEventsResult struct {
+	// Total is the sum of the results of all the events.
+	Total SendEventResult
+
+	// Events are the results of each event (in the same order as the events).
+	Events []SendEventResult
+}
+
+func (r *SendEventResult) add(other SendEventResult) {
+	r.SentCountImmediate += other.SentCountImmediate
+	r.SentCountDeferred += other.SentCountDeferred
+	r.PiledCount += other.PiledCount
+	r.DropCountImmediate += other.DropCountImmediate
+	r.DropCountDeferred += other.DropCountDeferred
+}
+
+// SendEvents is the same as SendEvent, but for multiple events.
+func SendEvents[E any](
+	ctx context.Context,
+	bus *EventBus,
+	events []E,
+) SendEventsResult {
+	var zeroValue E
+	return SendEventsWithCustomTopic(ctx, bus, zeroValue, events)
+}
+
+// SendEventsWithCustomTopic sends the events in order, as consecutive calls
+// of SendEventWithCustomTopic would, but checks the topic and the tracing
+// and reads the subscriptions only once (unless BusOptionRetainLastEvents
+// is set); the BeforeSend hooks of all the events are called before
+// sending the first of them.
+func SendEventsWithCustomTopic[T, E any](
+	ctx context.Context,
+	bus *EventBus,
+	topic T,
+	events []E,
+) SendEventsResult {
+	result, _ := SendEventsWithCustomTopicWithError(ctx, bus, topic, events)
+	return result
+}
+
+// SendEventsWithError is the same as SendEvents, but also returns
+// the reason the events were not sent (see SendEventWithError).
+func SendEventsWithError[E any](
+	ctx context.Context,
+	bus *EventBus,
+	events []E,
+) (SendEventsResult, error) {
+	var zeroValue E
+	return SendEventsWithCustomTopicWithError(ctx, bus, zeroValue, events)
+}
+
+// SendEventsWithCustomTopicWithError is the same as SendEventsWithCustomTopic,
+// but also returns the reason the events were not sent: ErrBusClosed,
+// ErrTopicTypeMismatch or ErrContextDone (the first of the errors
+// of the events, see SendEventWithCustomTopicWithError).
+func SendEventsWithCustomTopicWithError[T, E any](
+	ctx context.Context,
+	bus *EventBus,
+	topic T,
+	events []E,
+) (result SendEventsResult, err error) {
+	if len(events) == 0 {
+		return
+	}
+	traceEnabled := isTraceEnabled(ctx)
+	if traceEnabled {
+		ctx = belt.WithField(ctx, "topic", fmt.Sprintf("%#+v", topic))
+		logger.Tracef(ctx, "SendEventsWithCustomTopic[%T, %T]: %d events", topic, events, len(events))
+		defer func() {
+			logger.Tracef(ctx, "/SendEventsWithCustomTopic[%T, %T]: %v %v", topic, events, result.Total, err)
+		}()
+	}
+
+	result.Events = make([]SendEventResult, len(events))
+	if err = checkSend[T, E](ctx, bus, topic); err != nil {
+		return
+	}
+
+	// the hooks of all the events are called before reading
+	// the subscriptions (see pendingSend.beginChecked)
+	sends := make([]pendingSend[T, E], len(events))
+	for idx, event := range events {
+		s := &sends[idx]
+		*s = pendingSend[T, E]{bus: bus, topic: topic, event: event, traceEnabled: traceEnabled}
+		s.runHooks(ctx)
+	}
+	var subs []any
+	if state := bus.getTopicState(topic); state != nil {
+		subs = state.loadSubscriptions()
+	}
+	for idx := range sends {
+		s := &sends[idx]
+		switch {
+		case bus.closed.Load():
+			s.err = ErrBusClosed
+		case bus.retainLastEvents:
+			// the events are retained one by one, and the subscriptions
+			// are to be read together with retaining each of them
+			s.dispatch(loadSubscriptionsForSend(bus, topic, s.event))
+		default:
+			s.dispatch(subs)
+		}
+		s.finish()
+		result.Events[idx] = s.result
+		result.Total.add(s.result)
+		if err == nil {
+			err = s.err
+		}
+	}
+	return
+}
@@ -0,0 +1,191 @@
+package eventbus
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// SpillCodec serializes the events spilled to disk (see OnOverflowSpillToDisk).
+//
+// For example, *eventbuscodec.Registry implements it.
+type SpillCodec interface {
+	MarshalEvent(event any) (typeName string, payload []byte, err error)
+	UnmarshalEvent(typeName string, payload []byte) (any, error)
+}
+
+const spillRecordHeaderSize = 8
+
+// spillQueue is a FIFO queue that keeps up to memoryLimit events in memory,
+// and the rest in a temporary file.
+type spillQueue[E any] struct {
+	locker      sync.Mutex
+	memory      []E
+	memoryLimit uint
+	dir         string
+	codec       SpillCodec
+	file        *os.File
+	fileCount   uint
+	readPos     int64
+	writePos    int64
+	fileHead    *E
+	notifier    chan struct{}
+	closed      bool
+}
+
+func newSpillQueue[E any](
+	memoryLimit uint,
+	dir string,
+	codec SpillCodec,
+) *spillQueue[E] {
+	return &spillQueue[E]{
+		memoryLimit: memoryLimit,
+		dir:         dir,
+		codec:       codec,
+		notifier:    make(chan struct{}, 1),
+	}
+}
+
+func (q *spillQueue[E]) Len() uint {
+	q.locker.Lock()
+	defer q.locker.Unlock()
+	return q.lenLocked()
+}
+
+func (q *spillQueue[E]) lenLocked() uint {
+	return uint(len(q.memory)) + q.fileCount
+}
+
+// pushLocked adds an event to the end of the queue.
+func (q *spillQueue[E]) pushLocked(ev E) error {
+	if q.fileCount == 0 && uint(len(q.memory)) < q.memoryLimit {
+		q.memory = append(q.memory, ev)
+		q.notify()
+		return nil
+	}
+	if err := q.writeToFile(ev); err != nil {
+		return err
+	}
+	q.fileCount++
+	q.notify()
+	return nil
+}
+
+func (q *spillQueue[E]) notify() {
+	select {
+	case q.notifier <- struct{}{}:
+	default:
+	}
+}
+
+func (q *spillQueue[E]) writeToFile(ev E) error {
+	if q.file == nil {
+		f, err := os.CreateTemp(q.dir, "eventbus-spill-*")
+		if err != nil {
+			return fmt.Errorf("unable to create a temporary file: %w", err)
+		}
+		q.file = f
+	}
+	typeName, payload, err := q.codec.MarshalEvent(ev)
+	if err != nil {
+		return fmt.Errorf("unable to serialize %T: %w", ev, err)
+	}
+	record := make([]byte, spillRecordHeaderSize+len(typeName)+len(payload))
+	binary.BigEndian.PutUint32(record[0:4], uint32(len(typeName)))
+	binary.BigEndian.PutUint32(record[4:8], uint32(len(payload)))
+	copy(record[spillRecordHeaderSize:], typeName)
+	copy(record[spillRecordHeaderSize+len(typeName):], payload)
+	if _, err := q.file.WriteAt(record, q.writePos); err != nil {
+		return fmt.Errorf("unable to write to '%s': %w", q.file.Name(), err)
+	}
+	q.writePos += int64(len(record))
+	return nil
+}
+
+// Peek returns the first event of the queue without removing it.
+func (q *spillQueue[E]) Peek() (E, bool, error) {
+	q.locker.Lock()
+	defer q.locker.Unlock()
+	if len(q.memory) > 0 {
+		return q.memory[0], true, nil
+	}
+	var zeroValue E
+	if q.fileCount == 0 {
+		return zeroValue, false, nil
+	}
+	if q.fileHead == nil {
+		ev, err := q.readFromFile()
+		if err != nil {
+			return zeroValue, false, err
+		}
+		q.fileHead = &ev
+	}
+	return *q.fileHead, true, nil
+}
+
+func (q *spillQueue[E]) readFromFile() (E, error) {
+	var zeroValue E
+	var header [spillRecordHeaderSize]byte
+	if _, err := q.file.ReadAt(header[:], q.readPos); err != nil {
+		return zeroValue, fmt.Errorf("unable to read from '%s': %w", q.file.Name(), err)
+	}
+	typeNameLen := binary.BigEndian.Uint32(header[0:4])
+	payloadLen := binary.BigEndian.Uint32(header[4:8])
+	body := make([]byte, int(typeNameLen)+int(payloadLen))
+	if _, err := q.file.ReadAt(body, q.readPos+spillRecordHeaderSize); err != nil && err != io.EOF {
+		return zeroValue, fmt.Errorf("unable to read from '%s': %w", q.file.Name(), err)
+	}
+	_ev, err := q.codec.UnmarshalEvent(string(body[:typeNameLen]), body[typeNameLen:])
+	if err != nil {
+		return zeroValue, fmt.Errorf("unable to deserialize: %w", err)
+	}
+	ev, ok := _ev.(E)
+	if !ok {
+		return zeroValue, fmt.Errorf("invalid type %T, expected %T", _ev, zeroValue)
+	}
+	q.readPos += spillRecordHeaderSize + int64(len(body))
+	return ev, nil
+}
+
+// Pop removes the first event of the queue (previously returned by Peek).
+func (q *spillQueue[E]) Pop() {
+	q.locker.Lock()
+	defer q.locker.Unlock()
+	if len(q.memory) > 0 {
+		var zeroValue E
+		q.memory[0] = zeroValue
+		q.memory = q.memory[1:]
+		if len(q.memory) == 0 {
+			q.memory = nil // releasing the memory
+		}
+		return
+	}
+	if q.fileHead == nil {
+		return
+	}
+	q.fileHead = nil
+	q.fileCount--
+	if q.fileCount == 0 {
+		// reclaiming the disk space
+		q.file.Truncate(0)
+		q.readPos, q.writePos = 0, 0
+	}
+}
+
+// Close releases the resources (including removing the temporary file).
+// The events pushed after that are dropped (see sendEventSpilling).
+func (q *spillQueue[E]) Close() {
+	q.locker.Lock()
+	defer q.locker.Unlock()
+	q.closed = true
+	q.memory = nil
+	q.fileCount = 0
+	q.fileHead = nil
+	if q.file != nil {
+		q.file.Close()
+		os.Remove(q.file.Name())
+		q.file = nil
+	}
+}
@@ -0,0 +1,99 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/facebookincubator/go-belt/tool/logger"
+)
+
+// BusOptionStrictTypes makes the EventBus to bind each topic to the event
+// type it is first used with (by subscribing, publishing or GetTopicWithCustomTopic)
+// and to reject the usage of the topic with another event type:
+// such a subscribing returns nil, such a publishing sends nothing,
+// and GetTopicWithCustomTopic returns ErrTopicTypeMismatch
+// (see also BusOptionPanicOnTypeMismatch and EventBus.TopicEventType).
+//
+// The subscriptions receiving EventWithContext[E] are bound to E.
+//
+// The bindings are kept until removed by EventBus.UnbindTopicEventType,
+// thus dynamic topics (e.g. per-request ones) are to be unbound
+// when they are not used anymore.
+type BusOptionStrictTypes bool
+
+func (opt BusOptionStrictTypes) applyToBus(cfg *busConfig) {
+	cfg.strictTypes = bool(opt)
+}
+
+// BusOptionPanicOnTypeMismatch makes an EventBus with BusOptionStrictTypes
+// to panic on a type mismatch instead of logging it (e.g. in debug builds).
+type BusOptionPanicOnTypeMismatch bool
+
+func (opt BusOptionPanicOnTypeMismatch) applyToBus(cfg *busConfig) {
+	cfg.panicOnTypeMismatch = bool(opt)
+}
+
+// TopicEventType returns the event type the topic is bound to
+// (see BusOptionStrictTypes).
+func (bus *EventBus) TopicEventType(topic any) (reflect.Type, bool) {
+	typ, ok := bus.topicTypes.Load(topic)
+	if !ok {
+		return nil, false
+	}
+	return typ.(reflect.Type), true
+}
+
+// UnbindTopicEventType removes the binding of the topic to its event type
+// (see BusOptionStrictTypes), so that the topic is bound again on its
+// next usage. It does not affect the existing subscriptions.
+func (bus *EventBus) UnbindTopicEventType(topic any) {
+	bus.topicTypes.Delete(topic)
+}
+
+type eventWithContext interface {
+	eventType() reflect.Type
+}
+
+func (EventWithContext[E]) eventType() reflect.Type {
+	return reflect.TypeFor[E]()
+}
+
+// eventTypeOf returns the type of the events of subscriptions
+// receiving E (and of publishers sending E).
+func eventTypeOf[E any]() reflect.Type {
+	var zeroValue E
+	if ev, ok := any(zeroValue).(eventWithContext); ok {
+		return ev.eventType()
+	}
+	return reflect.TypeFor[E]()
+}
+
+// checkEventType binds the topic to the event type E (if strict types are
+// enabled and the topic is not bound yet), and returns ErrTopicTypeMismatch
+// if the topic is bound to another type.
+func checkEventType[T, E any](
+	bus *EventBus,
+	topic T,
+) error {
+	if !bus.strictTypes {
+		return nil
+	}
+	typ := eventTypeOf[E]()
+	_boundType, loaded := bus.topicTypes.LoadOrStore(topic, typ)
+	if !loaded {
+		return nil
+	}
+	boundType := _boundType.(reflect.Type)
+	if boundType == typ {
+		return nil
+	}
+	return fmt.Errorf("%w: the topic %#+v is bound to %v, not %v", ErrTopicTypeMismatch, topic, boundType, typ)
+}
+
+func (bus *EventBus) reportTypeMismatch(ctx context.Context, err error) {
+	if bus.panicOnTypeMismatch {
+		panic(err)
+	}
+	logger.Error(ctx, err)
+}
@@ -3,13 +3,17 @@ package eventbus
 import (
 	"context"
 	"fmt"
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/facebookincubator/go-belt/tool/logger"
 )
 
 type Subscription[T, E any] struct {
+	id              uint64
+	creatorStack    []byte
 	canceler        *triggerable
 	readier         *triggerable
 	finished        *triggerable
@@ -18,6 +22,26 @@ type Subscription[T, E any] struct {
 	eventChan       chan E
 	eventChanLocker sync.RWMutex
 	pile            chan E
+	spill           *spillQueue[E]
+	deliveredCount  atomic.Uint64
+
+	// piledCount is the amount of events in the pile, including the one
+	// being moved from the pile to eventChan by pileHandler.
+	piledCount atomic.Int64
+
+	// orderLocker and lastDeferredSendDone are used to preserve
+	// the order of deferred sends (see OptionOrderedDelivery).
+	orderLocker          sync.Mutex
+	lastDeferredSendDone chan struct{}
+
+	// closeReason is the reason the subscription was closed by the EventBus
+	// (see Err).
+	closeReason atomic.Pointer[error]
+
+	// queue is the same channel as eventChan, but it is never reset to nil
+	// (thus could be used without locking eventChanLocker).
+	queue chan E
+
 	config
 }
 
@@ -29,6 +53,7 @@ func newSubscription[T, E any](
 ) *Subscription[T, E] {
 	cfg := Options(opts).Config()
 	sub := &Subscription[T, E]{
+		id:        lastSubscriptionID.Add(1),
 		canceler:  newTriggerable(ctx),
 		readier:   newTriggerable(ctx),
 		finished:  newTriggerable(context.Background()),
@@ -37,22 +62,93 @@ func newSubscription[T, E any](
 		eventChan: make(chan E, cfg.queueSize),
 		config:    cfg,
 	}
+	sub.queue = sub.eventChan
+	if bus.diagnostics != nil {
+		sub.creatorStack = debug.Stack()
+	}
 	switch onOverflow := cfg.onOverflow.(type) {
 	case onOverflowPileUpOrClose:
 		sub.pile = make(chan E, onOverflow.PileSize)
 		go sub.pileHandler(ctx)
+	case onOverflowSpillToDisk:
+		sub.spill = newSpillQueue[E](onOverflow.MemoryPileSize, onOverflow.Dir, onOverflow.Codec)
+		go sub.spillHandler(ctx)
 	}
 	return sub
 }
 
+// EventChan returns the channel of the events; it is closed when
+// the subscription is finished.
 func (sub *Subscription[T, E]) EventChan() chan E {
-	return sub.eventChan
+	// not sub.eventChan, since it is reset on unsubscribing
+	return sub.queue
 }
 
 func (sub *Subscription[T, E]) Finish(ctx context.Context) bool {
 	return UnsubscribeWithCustomTopic(ctx, sub.eventBus, sub.topic, sub)
 }
 
+// FinishWithError is the same as Finish, but returns the reason
+// of a failure: ErrNotSubscribed or ErrContextDone.
+func (sub *Subscription[T, E]) FinishWithError(ctx context.Context) error {
+	return UnsubscribeWithCustomTopicWithError(ctx, sub.eventBus, sub.topic, sub)
+}
+
+// ID returns the unique identifier of the subscription.
+func (sub *Subscription[T, E]) ID() uint64 {
+	return sub.id
+}
+
+// CreatorStack returns the stack trace of the subscription's creator
+// if the diagnostics are enabled (see BusOptionDiagnostics).
+func (sub *Subscription[T, E]) CreatorStack() []byte {
+	return sub.creatorStack
+}
+
+// Topic returns the topic of the subscription.
+func (sub *Subscription[T, E]) Topic() T {
+	return sub.topic
+}
+
+// Backlog returns the amount of events already accepted
+// for the subscriber, but not received by it yet.
+func (sub *Subscription[T, E]) Backlog() uint {
+	backlog := uint(len(sub.queue) + len(sub.pile))
+	if sub.spill != nil {
+		backlog += sub.spill.Len()
+	}
+	return backlog
+}
+
+// QueueSize returns the size of the event channel.
+func (sub *Subscription[T, E]) QueueSize() uint {
+	return sub.queueSize
+}
+
+// DeliveredCount returns the amount of events put to the event channel
+// (including the events being put at the moment).
+func (sub *Subscription[T, E]) DeliveredCount() uint64 {
+	return sub.deliveredCount.Load()
+}
+
+// addDeliveredCount is called with 1 before handing an event over to
+// the event channel, and with -1 if it was not handed over after all:
+// counting after the handing over would let the subscriber receive
+// the event before it is counted (see ReceivedCount).
+func (sub *Subscription[T, E]) addDeliveredCount(delta int64) {
+	sub.deliveredCount.Add(uint64(delta))
+}
+
+// ReceivedCount returns the amount of events received by the subscriber
+// from the event channel (including the events being put to the channel
+// at the moment, see DeliveredCount).
+func (sub *Subscription[T, E]) ReceivedCount() uint64 {
+	// the length is read first: every event in the channel
+	// is already counted as delivered
+	queueLen := uint64(len(sub.queue))
+	return sub.deliveredCount.Load() - queueLen
+}
+
 type sendEventToSubResult int
 
 const (
@@ -107,6 +203,7 @@ func (sub *Subscription[T, E]) pileHandler(
 		case ev = <-sub.pile:
 		}
 		func() {
+			defer sub.piledCount.Add(-1)
 			sub.eventChanLocker.RLock()
 			defer sub.eventChanLocker.RUnlock()
 			eventChan := sub.eventChan
@@ -120,12 +217,18 @@ func (sub *Subscription[T, E]) pileHandler(
 				}
 				waitCtx, cancelFn = context.WithTimeout(ctx, onOverflow.Timeout)
 			}
+			sub.addDeliveredCount(1)
 			select {
 			case <-waitCtx.Done():
+				sub.addDeliveredCount(-1)
 				// timed out, closing:
+				if ctx.Err() == nil {
+					sub.setCloseReason(ErrSubscriptionOverflow)
+				}
 				UnsubscribeWithCustomTopic(ctx, sub.eventBus, sub.topic, sub)
 				return
 			case <-sub.Done():
+				sub.addDeliveredCount(-1)
 				return
 			case eventChan <- ev:
 			}
@@ -144,9 +247,21 @@ func (sub *Subscription[T, E]) doSendEvent(
 	default:
 	}
 
+	if sub.spill != nil {
+		return sub.sendEventSpilling(ctx, event)
+	}
+
+	if sub.orderedDelivery {
+		sub.orderLocker.Lock()
+		defer sub.orderLocker.Unlock()
+		if sub.hasPendingDeferredSendLocked() {
+			return sendEventToSubResultDeferred
+		}
+	}
+
 	// the locking is to prevent `sub.eventChan` from closing
 	var eventChan chan E
-	if len(sub.pile) == 0 {
+	if sub.piledCount.Load() == 0 {
 		sub.eventChanLocker.RLock()
 		defer sub.eventChanLocker.RUnlock()
 		eventChan = sub.eventChan
@@ -154,18 +269,130 @@ func (sub *Subscription[T, E]) doSendEvent(
 			return sendEventToSubResultDropped
 		}
 	}
-	return handleSubChans(
+	sub.addDeliveredCount(1)
+	r := handleSubChans(
 		ctx,
 		eventChan, sub.pile, sub.canceler.Done(),
 		event,
 		deferrable, sub.onOverflow,
 	)
+	if r != sendEventToSubResultSent {
+		sub.addDeliveredCount(-1)
+	}
+	if r == sendEventToSubResultPiled {
+		sub.piledCount.Add(1)
+	}
+	return r
+}
+
+func (sub *Subscription[T, E]) sendEventSpilling(
+	ctx context.Context,
+	event E,
+) sendEventToSubResult {
+	sub.eventChanLocker.RLock()
+	defer sub.eventChanLocker.RUnlock()
+	eventChan := sub.eventChan
+	if eventChan == nil {
+		return sendEventToSubResultDropped
+	}
+
+	// locking the queue for the whole function to preserve the order of events
+	sub.spill.locker.Lock()
+	defer sub.spill.locker.Unlock()
+	if sub.spill.closed {
+		// the spill handler has exited, a new temporary file would never be removed
+		return sendEventToSubResultDropped
+	}
+	if sub.spill.lenLocked() == 0 {
+		sub.addDeliveredCount(1)
+		select {
+		case eventChan <- event:
+			return sendEventToSubResultSent
+		default:
+			sub.addDeliveredCount(-1)
+		}
+	}
+	if err := sub.spill.pushLocked(event); err != nil {
+		logger.Errorf(ctx, "unable to spill the event: %v", err)
+		sub.setCloseReason(fmt.Errorf("unable to spill an event: %w", err))
+		return sendEventToSubResultDroppedUnsubscribe
+	}
+	return sendEventToSubResultPiled
+}
+
+func (sub *Subscription[T, E]) spillHandler(
+	ctx context.Context,
+) {
+	if isTraceEnabled(ctx) {
+		var sample E
+		logger.Tracef(ctx, "spillHandler[%T](ctx)", sample)
+		defer func() { logger.Tracef(ctx, "/spillHandler[%T](ctx)", sample) }()
+	}
+	defer sub.spill.Close()
+
+	for {
+		ev, ok, err := sub.spill.Peek()
+		if err != nil {
+			logger.Errorf(ctx, "unable to read a spilled event: %v", err)
+			sub.setCloseReason(fmt.Errorf("unable to read a spilled event: %w", err))
+			UnsubscribeWithCustomTopic(ctx, sub.eventBus, sub.topic, sub)
+			return
+		}
+		if !ok {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sub.Done():
+				return
+			case <-sub.spill.notifier:
+			}
+			continue
+		}
+		sent := func() bool {
+			sub.eventChanLocker.RLock()
+			defer sub.eventChanLocker.RUnlock()
+			eventChan := sub.eventChan
+			if eventChan == nil {
+				return false
+			}
+			sub.addDeliveredCount(1)
+			select {
+			case <-ctx.Done():
+			case <-sub.Done():
+			case eventChan <- ev:
+				return true
+			}
+			sub.addDeliveredCount(-1)
+			return false
+		}()
+		if !sent {
+			return
+		}
+		sub.spill.Pop()
+	}
 }
 
 func (sub *Subscription[T, E]) Done() <-chan struct{} {
 	return sub.canceler.Done()
 }
 
+// Err returns the reason the subscription was closed by the EventBus
+// (e.g. ErrSubscriptionOverflow or ErrBusClosed), or nil if it is not
+// closed or was finished by the subscriber.
+func (sub *Subscription[T, E]) Err() error {
+	err := sub.closeReason.Load()
+	if err == nil {
+		return nil
+	}
+	return *err
+}
+
+// setCloseReason sets the reason of closing the subscription
+// (if it is not set yet); to be called before unsubscribing.
+func (sub *Subscription[T, E]) setCloseReason(err error) {
+	sub.closeReason.CompareAndSwap(nil, &err)
+}
+
 func (sub *Subscription[T, E]) Cancel() {
 	sub.canceler.Trigger()
 }
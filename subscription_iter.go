@@ -0,0 +1,51 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"iter"
+
+	"github.com/xaionaro-go/xcontext"
+)
+
+// All returns an iterator over the events of the subscription, which ends
+// when the subscription is closed or ctx is done (see AllWithError).
+//
+// The subscription is finished when the iteration ends (including
+// breaking out of the loop), thus it is to be iterated only once.
+func (sub *Subscription[T, E]) All(ctx context.Context) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		for ev, err := range sub.AllWithError(ctx) {
+			if err != nil || !yield(ev) {
+				return
+			}
+		}
+	}
+}
+
+// AllWithError is the same as All, but if the iteration ends not because
+// of the subscriber, the last yielded pair is the reason: ErrContextDone,
+// ErrBusClosed, ErrSubscriptionOverflow, etc (see Err).
+func (sub *Subscription[T, E]) AllWithError(ctx context.Context) iter.Seq2[E, error] {
+	return func(yield func(E, error) bool) {
+		defer sub.Finish(xcontext.DetachDone(ctx))
+		var zeroValue E
+		for {
+			select {
+			case <-ctx.Done():
+				yield(zeroValue, fmt.Errorf("%w: %w", ErrContextDone, ctx.Err()))
+				return
+			case ev, ok := <-sub.queue:
+				if !ok {
+					if err := sub.Err(); err != nil {
+						yield(zeroValue, err)
+					}
+					return
+				}
+				if !yield(ev, nil) {
+					return
+				}
+			}
+		}
+	}
+}
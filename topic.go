@@ -0,0 +1,143 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/facebookincubator/go-belt/tool/logger"
+)
+
+// Topic is a handle of a topic of an EventBus with a fixed event type.
+//
+// Sending via the handle does not look up the topic and
+// does not check the type of each subscription on every send.
+type Topic[T, E any] struct {
+	bus   *EventBus
+	topic T
+	state *topicState
+
+	// typedSubs is the subscriptions of the topic by type,
+	// rebuilt when the subscriptions of the topic change.
+	typedSubs atomic.Pointer[typedSubscriptions[T, E]]
+}
+
+type typedSubscriptions[T, E any] struct {
+	source      *[]any
+	subs        []*Subscription[T, E]
+	subsWithCtx []*Subscription[T, EventWithContext[E]]
+}
+
+// GetTopic returns the handle of the topic of events of type E.
+func GetTopic[E any](
+	ctx context.Context,
+	bus *EventBus,
+) (*Topic[E, E], error) {
+	var zeroValue E
+	return GetTopicWithCustomTopic[E, E](ctx, bus, zeroValue)
+}
+
+// GetTopicWithCustomTopic returns the handle of the topic.
+//
+// It returns ErrTopicTypeMismatch if the topic already has subscriptions
+// of another event type (or is bound to another type, see BusOptionStrictTypes).
+//
+// The state of the topic is kept by the EventBus for as long as the EventBus
+// exists, thus the handles are to be taken for long-living topics.
+func GetTopicWithCustomTopic[T, E any](
+	ctx context.Context,
+	bus *EventBus,
+	topic T,
+) (*Topic[T, E], error) {
+	if err := checkEventType[T, E](bus, topic); err != nil {
+		return nil, err
+	}
+	state := bus.lockTopicState(ctx, topic)
+	if state == nil {
+		return nil, fmt.Errorf("%w: %w", ErrContextDone, ctx.Err())
+	}
+	defer state.Unlock()
+	for _, _sub := range state.loadSubscriptions() {
+		switch _sub.(type) {
+		case *Subscription[T, E], *Subscription[T, EventWithContext[E]]:
+		default:
+			return nil, fmt.Errorf("%w: the topic %#+v has a subscription %T, expected %T", ErrTopicTypeMismatch, topic, _sub, (*Subscription[T, E])(nil))
+		}
+	}
+	state.pinned = true
+	return &Topic[T, E]{
+		bus:   bus,
+		topic: topic,
+		state: state,
+	}, nil
+}
+
+// Topic returns the topic.
+func (t *Topic[T, E]) Topic() T {
+	return t.topic
+}
+
+// Send is the same as SendEventWithCustomTopic.
+func (t *Topic[T, E]) Send(
+	ctx context.Context,
+	event E,
+) SendEventResult {
+	s := pendingSend[T, E]{bus: t.bus, topic: t.topic, event: event, handle: t}
+	s.begin(ctx)
+	s.finish()
+	return s.result
+}
+
+// SendWithError is the same as SendEventWithCustomTopicWithError.
+func (t *Topic[T, E]) SendWithError(
+	ctx context.Context,
+	event E,
+) (SendEventResult, error) {
+	s := pendingSend[T, E]{bus: t.bus, topic: t.topic, event: event, handle: t}
+	s.begin(ctx)
+	s.finish()
+	return s.result, s.err
+}
+
+// Subscribe is the same as SubscribeWithCustomTopic.
+func (t *Topic[T, E]) Subscribe(
+	ctx context.Context,
+	opts ...Option,
+) *Subscription[T, E] {
+	return SubscribeWithCustomTopic[T, E](ctx, t.bus, t.topic, opts...)
+}
+
+func (t *Topic[T, E]) loadTypedSubscriptions(
+	ctx context.Context,
+	event E,
+) *typedSubscriptions[T, E] {
+	var source *[]any
+	if t.bus.retainLastEvents {
+		t.state.retainedLocker.Lock()
+		t.state.retained, t.state.hasRetained = event, true
+		source = t.state.subscriptions.Load()
+		t.state.retainedLocker.Unlock()
+	} else {
+		source = t.state.subscriptions.Load()
+	}
+
+	typedSubs := t.typedSubs.Load()
+	if typedSubs != nil && typedSubs.source == source {
+		return typedSubs
+	}
+	typedSubs = &typedSubscriptions[T, E]{source: source}
+	if source != nil {
+		for _, _sub := range *source {
+			switch sub := _sub.(type) {
+			case *Subscription[T, E]:
+				typedSubs.subs = append(typedSubs.subs, sub)
+			case *Subscription[T, EventWithContext[E]]:
+				typedSubs.subsWithCtx = append(typedSubs.subsWithCtx, sub)
+			default:
+				logger.Errorf(ctx, "invalid type %T, expected %T", _sub, (*Subscription[T, E])(nil))
+			}
+		}
+	}
+	t.typedSubs.Store(typedSubs)
+	return typedSubs
+}
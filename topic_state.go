@@ -0,0 +1,129 @@
+package eventbus
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+// topicState is the state of a topic of an EventBus.
+type topicState struct {
+	// chanLocker serializes subscribing and unsubscribing of the topic,
+	// so that unrelated topics do not contend with each other.
+	chanLocker
+
+	// subscriptions is an immutable snapshot of the subscriptions of the topic.
+	// It is replaced (with the topic locked) on each subscribe and unsubscribe,
+	// so that publishers could read it without locking.
+	subscriptions atomic.Pointer[[]any]
+
+	// retainedLocker makes setting the retained event together with
+	// reading the snapshot atomic against subscribing
+	// (to deliver the retained event exactly once, see OptionReceiveRetained).
+	retainedLocker sync.Mutex
+	retained       any
+	hasRetained    bool
+
+	// pinned is set (with the topic locked) if the state is referenced
+	// by a Topic handle, so it is never removed.
+	pinned bool
+
+	// removed is set (with the topic and retainedLocker locked) when
+	// the state is removed from the EventBus; the ones who modify
+	// a removed state are to retry with a new one.
+	removed bool
+}
+
+func (bus *EventBus) getTopicState(topic any) *topicState {
+	state, ok := bus.topics.Load(topic)
+	if !ok {
+		return nil
+	}
+	return state.(*topicState)
+}
+
+func (bus *EventBus) getOrCreateTopicState(topic any) *topicState {
+	if state := bus.getTopicState(topic); state != nil {
+		return state
+	}
+	state, _ := bus.topics.LoadOrStore(topic, &topicState{
+		chanLocker: make(chanLocker, 1),
+	})
+	return state.(*topicState)
+}
+
+// lockTopicState returns the locked state of the topic (see topicState.chanLocker),
+// or nil if ctx is done.
+func (bus *EventBus) lockTopicState(
+	ctx context.Context,
+	topic any,
+) *topicState {
+	for {
+		state := bus.getOrCreateTopicState(topic)
+		if !state.Lock(ctx) {
+			return nil
+		}
+		if !state.removed {
+			return state
+		}
+		state.Unlock()
+	}
+}
+
+// lockTopicStateRetained returns the state of the topic
+// with retainedLocker locked.
+func (bus *EventBus) lockTopicStateRetained(topic any) *topicState {
+	for {
+		state := bus.getOrCreateTopicState(topic)
+		state.retainedLocker.Lock()
+		if !state.removed {
+			return state
+		}
+		state.retainedLocker.Unlock()
+	}
+}
+
+// removeIfUnused removes the state from the EventBus if it has no
+// subscriptions, no retained event and no Topic handles, so that
+// dynamic topics do not pile up.
+//
+// It is to be called with the topic locked.
+func (s *topicState) removeIfUnused(bus *EventBus, topic any) {
+	if s.pinned || len(s.loadSubscriptions()) > 0 {
+		return
+	}
+	s.retainedLocker.Lock()
+	defer s.retainedLocker.Unlock()
+	if s.hasRetained {
+		return
+	}
+	s.removed = true
+	bus.topics.CompareAndDelete(topic, s)
+}
+
+func (s *topicState) loadSubscriptions() []any {
+	subs := s.subscriptions.Load()
+	if subs == nil {
+		return nil
+	}
+	return *subs
+}
+
+// addSubscription is to be called with the topic locked.
+func (s *topicState) addSubscription(sub any) {
+	subs := append(slices.Clone(s.loadSubscriptions()), sub)
+	s.subscriptions.Store(&subs)
+}
+
+// removeSubscription is to be called with the topic locked.
+func (s *topicState) removeSubscription(sub any) bool {
+	oldSubs := s.loadSubscriptions()
+	idx := slices.Index(oldSubs, sub)
+	if idx < 0 {
+		return false
+	}
+	subs := slices.Delete(slices.Clone(oldSubs), idx, idx+1)
+	s.subscriptions.Store(&subs)
+	return true
+}
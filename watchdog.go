@@ -0,0 +1,245 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/facebookincubator/go-belt/tool/logger"
+)
+
+// SlowConsumerEvent describes a subscription that exceeded the thresholds
+// of a Watchdog.
+type SlowConsumerEvent struct {
+	Topic        any
+	Subscription AbstractSubscription
+	Backlog      uint
+	QueueSize    uint
+	StalledFor   time.Duration
+}
+
+// SlowConsumerHook is called by a Watchdog when a subscription becomes slow.
+//
+// It is called once per slowness episode: the hook is not called again
+// until the subscription recovers.
+type SlowConsumerHook interface {
+	OnSlowConsumer(ctx context.Context, ev SlowConsumerEvent)
+}
+
+// SlowConsumerHookFunc is a SlowConsumerHook defined by a function.
+type SlowConsumerHookFunc func(ctx context.Context, ev SlowConsumerEvent)
+
+var _ SlowConsumerHook = SlowConsumerHookFunc(nil)
+
+func (fn SlowConsumerHookFunc) OnSlowConsumer(ctx context.Context, ev SlowConsumerEvent) {
+	fn(ctx, ev)
+}
+
+// SlowConsumerHooks is a SlowConsumerHook that calls all of its items.
+type SlowConsumerHooks []SlowConsumerHook
+
+var _ SlowConsumerHook = SlowConsumerHooks(nil)
+
+func (s SlowConsumerHooks) OnSlowConsumer(ctx context.Context, ev SlowConsumerEvent) {
+	for _, hook := range s {
+		hook.OnSlowConsumer(ctx, ev)
+	}
+}
+
+// SlowConsumerLog is a SlowConsumerHook that logs a warning.
+type SlowConsumerLog struct{}
+
+var _ SlowConsumerHook = SlowConsumerLog{}
+
+func (SlowConsumerLog) OnSlowConsumer(ctx context.Context, ev SlowConsumerEvent) {
+	logger.Warnf(
+		ctx,
+		"slow consumer on topic %s: backlog %d/%d, stalled for %v",
+		fmt.Sprintf("%#+v", ev.Topic), ev.Backlog, ev.QueueSize, ev.StalledFor,
+	)
+}
+
+// SlowConsumerSendEvent is a SlowConsumerHook that sends the SlowConsumerEvent
+// to the given EventBus (thus subscribe to SlowConsumerEvent to receive them).
+//
+// The event is sent by SendEventAsync, so that a slow subscriber of
+// SlowConsumerEvent does not block the Watchdog: the deferred sends
+// (see OnOverflowWait) are dropped when the context of Watchdog.Serve is done.
+type SlowConsumerSendEvent struct {
+	Bus *EventBus
+}
+
+var _ SlowConsumerHook = SlowConsumerSendEvent{}
+
+func (h SlowConsumerSendEvent) OnSlowConsumer(ctx context.Context, ev SlowConsumerEvent) {
+	SendEventAsync(ctx, h.Bus, ev)
+}
+
+// SlowConsumerUnsubscribe is a SlowConsumerHook that closes the subscription.
+type SlowConsumerUnsubscribe struct{}
+
+var _ SlowConsumerHook = SlowConsumerUnsubscribe{}
+
+func (SlowConsumerUnsubscribe) OnSlowConsumer(ctx context.Context, ev SlowConsumerEvent) {
+	ev.Subscription.Finish(ctx)
+}
+
+// WatchdogOption is an option of a Watchdog.
+type WatchdogOption interface {
+	applyToWatchdog(*watchdogConfig)
+}
+
+type watchdogConfig struct {
+	interval   time.Duration
+	maxBacklog uint
+	maxStall   time.Duration
+}
+
+type WatchdogOptions []WatchdogOption
+
+func (s WatchdogOptions) Config() watchdogConfig {
+	cfg := watchdogConfig{
+		interval: time.Second,
+		maxStall: 10 * time.Second,
+	}
+	for _, opt := range s {
+		opt.applyToWatchdog(&cfg)
+	}
+	return cfg
+}
+
+// WatchdogOptionInterval sets how often the subscriptions are checked.
+type WatchdogOptionInterval time.Duration
+
+func (opt WatchdogOptionInterval) applyToWatchdog(cfg *watchdogConfig) {
+	cfg.interval = time.Duration(opt)
+}
+
+// WatchdogOptionMaxBacklog sets the backlog (see Subscription.Backlog)
+// considered slow. Zero disables the check.
+type WatchdogOptionMaxBacklog uint
+
+func (opt WatchdogOptionMaxBacklog) applyToWatchdog(cfg *watchdogConfig) {
+	cfg.maxBacklog = uint(opt)
+}
+
+// WatchdogOptionMaxStall sets for how long a subscriber with a non-empty
+// backlog may receive nothing before being considered slow.
+// Zero disables the check.
+type WatchdogOptionMaxStall time.Duration
+
+func (opt WatchdogOptionMaxStall) applyToWatchdog(cfg *watchdogConfig) {
+	cfg.maxStall = time.Duration(opt)
+}
+
+// Watchdog monitors subscriptions of an EventBus and calls a SlowConsumerHook
+// for the subscriptions which either have a too large backlog or have not
+// received anything for too long while having a backlog.
+type Watchdog struct {
+	bus    *EventBus
+	hook   SlowConsumerHook
+	config watchdogConfig
+	states map[AbstractSubscription]*watchdogSubState
+}
+
+type watchdogSubState struct {
+	receivedCount  uint64
+	lastProgressAt time.Time
+	isSlow         bool
+}
+
+func NewWatchdog(
+	bus *EventBus,
+	hook SlowConsumerHook,
+	opts ...WatchdogOption,
+) *Watchdog {
+	return &Watchdog{
+		bus:    bus,
+		hook:   hook,
+		config: WatchdogOptions(opts).Config(),
+		states: map[AbstractSubscription]*watchdogSubState{},
+	}
+}
+
+// Serve checks the subscriptions periodically until the context is closed.
+func (w *Watchdog) Serve(ctx context.Context) {
+	ticker := time.NewTicker(w.config.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		w.Check(ctx)
+	}
+}
+
+// Check checks the subscriptions once.
+//
+// It is not safe to call Check concurrently (including with Serve).
+func (w *Watchdog) Check(ctx context.Context) {
+	now := time.Now()
+	seen := map[AbstractSubscription]struct{}{}
+	for _, topic := range w.bus.Topics() {
+		for _, sub := range w.bus.AbstractSubscriptions(topic) {
+			seen[sub] = struct{}{}
+			if ev, ok := w.checkSubscription(now, topic, sub); ok {
+				w.hook.OnSlowConsumer(ctx, ev)
+			}
+		}
+	}
+	for sub := range w.states {
+		if _, ok := seen[sub]; !ok {
+			delete(w.states, sub)
+		}
+	}
+}
+
+func (w *Watchdog) checkSubscription(
+	now time.Time,
+	topic any,
+	sub AbstractSubscription,
+) (SlowConsumerEvent, bool) {
+	backlog := sub.Backlog()
+	receivedCount := sub.ReceivedCount()
+	state := w.states[sub]
+	if state == nil {
+		state = &watchdogSubState{
+			receivedCount:  receivedCount,
+			lastProgressAt: now,
+		}
+		w.states[sub] = state
+	}
+	// ReceivedCount includes the events being sent at the moment, which
+	// might be dropped later, thus only a count above the maximal
+	// seen one is considered a progress
+	if receivedCount > state.receivedCount {
+		state.receivedCount = receivedCount
+		state.lastProgressAt = now
+	}
+	if backlog == 0 {
+		state.lastProgressAt = now
+	}
+	stalledFor := now.Sub(state.lastProgressAt)
+
+	isSlow := false
+	if w.config.maxBacklog > 0 && backlog >= w.config.maxBacklog {
+		isSlow = true
+	}
+	if w.config.maxStall > 0 && stalledFor >= w.config.maxStall {
+		isSlow = true
+	}
+	wasSlow := state.isSlow
+	state.isSlow = isSlow
+	if !isSlow || wasSlow {
+		return SlowConsumerEvent{}, false
+	}
+	return SlowConsumerEvent{
+		Topic:        topic,
+		Subscription: sub,
+		Backlog:      backlog,
+		QueueSize:    sub.QueueSize(),
+		StalledFor:   stalledFor,
+	}, true
+}